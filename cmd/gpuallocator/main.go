@@ -0,0 +1,315 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Command gpuallocator inspects the GPU topology of a node and runs the
+// allocation policies of the gpuallocator package against it. All commands
+// accept --topology-file to work offline against a saved snapshot or captured
+// 'nvidia-smi topo -m' output.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/go-gpuallocator/gpuallocator"
+)
+
+const usage = `Usage: gpuallocator <command> [options]
+
+Commands:
+  topo      Print the GPU topology as a matrix, JSON, DOT or Mermaid graph
+  allocate  Run an allocation policy and print the selected GPUs
+  explain   Run an allocation policy and explain the score of the selection
+  snapshot  Write a JSON snapshot of the GPU topology
+
+Run 'gpuallocator <command> --help' for the options of a command.
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command specified by args and writes its output to w.
+func run(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(w, usage)
+		return fmt.Errorf("no command specified")
+	}
+
+	switch args[0] {
+	case "topo":
+		return runTopo(args[1:], w)
+	case "allocate":
+		return runAllocate(args[1:], w, false)
+	case "explain":
+		return runAllocate(args[1:], w, true)
+	case "snapshot":
+		return runSnapshot(args[1:], w)
+	case "help", "-h", "--help":
+		fmt.Fprint(w, usage)
+		return nil
+	}
+
+	fmt.Fprint(w, usage)
+	return fmt.Errorf("unknown command: %q", args[0])
+}
+
+// topologyFlags holds the options shared by all commands to select the
+// topology to operate on.
+type topologyFlags struct {
+	topologyFile   string
+	sysfsRoot      string
+	repairTopology bool
+}
+
+func (f *topologyFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.topologyFile, "topology-file", "", "read the topology from a JSON snapshot or 'nvidia-smi topo -m' output instead of NVML")
+	fs.StringVar(&f.sysfsRoot, "sysfs-root", "", "root of the sysfs mount used for NUMA and PCIe discovery")
+	fs.BoolVar(&f.repairTopology, "repair-topology", false, "repair an inconsistent link table reported by NVML instead of failing")
+}
+
+// options returns the options used to discover the devices through NVML.
+func (f *topologyFlags) options() []gpuallocator.Option {
+	var opts []gpuallocator.Option
+	if f.sysfsRoot != "" {
+		opts = append(opts, gpuallocator.WithSysfsRoot(f.sysfsRoot))
+	}
+	if f.repairTopology {
+		opts = append(opts, gpuallocator.WithTopologyRepair())
+	}
+	return opts
+}
+
+// devices returns the devices of the selected topology.
+func (f *topologyFlags) devices() (gpuallocator.DeviceList, error) {
+	if f.topologyFile == "" {
+		return gpuallocator.NewDevices(f.options()...)
+	}
+	if f.repairTopology {
+		return nil, fmt.Errorf("--repair-topology only applies to NVML discovery")
+	}
+
+	contents, err := os.ReadFile(f.topologyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading topology file: %v", err)
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(contents)), "{") {
+		return gpuallocator.ReadSnapshot(bytes.NewReader(contents))
+	}
+	return gpuallocator.ParseTopoMatrix(bytes.NewReader(contents))
+}
+
+func runTopo(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("topo", flag.ContinueOnError)
+	fs.SetOutput(w)
+	var topology topologyFlags
+	topology.register(fs)
+	format := fs.String("format", "matrix", "output format: matrix, json, dot or mermaid")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	devices, err := topology.devices()
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "matrix":
+		fmt.Fprint(w, devices.TopoMatrix())
+	case "json":
+		return devices.WriteSnapshot(w)
+	case "dot":
+		fmt.Fprint(w, devices.DOT())
+	case "mermaid":
+		fmt.Fprint(w, devices.Mermaid())
+	default:
+		return fmt.Errorf("unknown format: %q", *format)
+	}
+	return nil
+}
+
+func runSnapshot(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	fs.SetOutput(w)
+	var topology topologyFlags
+	topology.register(fs)
+	output := fs.String("output", "", "write the snapshot to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	devices, err := topology.devices()
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return devices.WriteSnapshot(w)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("error creating snapshot file: %v", err)
+	}
+
+	if err := devices.WriteSnapshot(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing snapshot file: %v", err)
+	}
+	return nil
+}
+
+// policies maps the names accepted by --policy to allocation policies.
+var policies = map[string]func() gpuallocator.Policy{
+	"simple":     gpuallocator.NewSimplePolicy,
+	"besteffort": func() gpuallocator.Policy { return gpuallocator.NewBestEffortPolicy() },
+	"bandwidth": func() gpuallocator.Policy {
+		return gpuallocator.NewBandwidthAwarePolicy(gpuallocator.SetObjectiveSum)
+	},
+	"ring": func() gpuallocator.Policy {
+		return gpuallocator.NewBestEffortPolicy(gpuallocator.WithSetObjective(gpuallocator.SetObjectiveRing))
+	},
+	"spread":  func() gpuallocator.Policy { return gpuallocator.NewSpreadPolicy() },
+	"packing": gpuallocator.NewPackingPolicy,
+}
+
+func policyNames() string {
+	return "simple, besteffort, bandwidth, ring, spread or packing"
+}
+
+func runAllocate(args []string, w io.Writer, explain bool) error {
+	name := "allocate"
+	if explain {
+		name = "explain"
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(w)
+	var topology topologyFlags
+	topology.register(fs)
+	policyName := fs.String("policy", "besteffort", "allocation policy: "+policyNames())
+	size := fs.Int("size", 1, "number of GPUs to allocate")
+	available := fs.String("available", "", "comma-separated indices of the GPUs available for allocation (default all)")
+	required := fs.String("required", "", "comma-separated indices of the GPUs that must be allocated")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	newPolicy, ok := policies[*policyName]
+	if !ok {
+		return fmt.Errorf("unknown policy %q, expected %v", *policyName, policyNames())
+	}
+
+	devices, err := topology.devices()
+	if err != nil {
+		return err
+	}
+
+	availableDevices := []*gpuallocator.Device(devices)
+	if *available != "" {
+		availableDevices, err = selectDevices(devices, *available)
+		if err != nil {
+			return fmt.Errorf("invalid --available: %v", err)
+		}
+	}
+	requiredDevices, err := selectDevices(devices, *required)
+	if err != nil {
+		return fmt.Errorf("invalid --required: %v", err)
+	}
+
+	allocated := newPolicy().Allocate(availableDevices, requiredDevices, *size)
+	if len(allocated) == 0 {
+		return fmt.Errorf("unable to allocate %d GPUs", *size)
+	}
+
+	allocation := gpuallocator.NewAllocation(allocated)
+	fmt.Fprintf(w, "GPUs: %v\n", joinIndices(allocated))
+	fmt.Fprintf(w, "BestEffort score: %d\n", gpuallocator.BestEffortSetScore(allocated))
+	fmt.Fprintf(w, "Ring: %v (bottleneck %d)\n", joinIndices(allocation.Devices), allocation.Bottleneck)
+	fmt.Fprintf(w, "CUDA_VISIBLE_DEVICES=%v\n", allocation.CUDAVisibleDevices())
+
+	if explain {
+		explainAllocation(w, allocated)
+	}
+	return nil
+}
+
+// explainAllocation prints the links and scores of each pair of GPUs in an
+// allocation.
+func explainAllocation(w io.Writer, allocated []*gpuallocator.Device) {
+	fmt.Fprintf(w, "Pairs:\n")
+	for i, gpu0 := range allocated {
+		for _, gpu1 := range allocated[i+1:] {
+			var types []string
+			for _, link := range gpu0.Links[gpu1.Index] {
+				types = append(types, link.Type.String())
+			}
+			if len(types) == 0 {
+				types = append(types, "none")
+			}
+			fmt.Fprintf(w, "  GPU %d - GPU %d: score %d, bandwidth %d MB/s (%v)\n",
+				gpu0.Index, gpu1.Index,
+				gpuallocator.BestEffortPairScore(gpu0, gpu1),
+				gpuallocator.BandwidthPairScore(gpu0, gpu1),
+				strings.Join(types, ", "))
+		}
+	}
+}
+
+// selectDevices returns the devices with the comma-separated indices.
+func selectDevices(devices gpuallocator.DeviceList, indices string) ([]*gpuallocator.Device, error) {
+	var selected []*gpuallocator.Device
+	if indices == "" {
+		return selected, nil
+	}
+	for _, field := range strings.Split(indices, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid GPU index %q", field)
+		}
+		found := false
+		for _, d := range devices {
+			if d.Index == index {
+				selected = append(selected, d)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no GPU with index %d", index)
+		}
+	}
+	return selected, nil
+}
+
+func joinIndices(devices []*gpuallocator.Device) string {
+	var indices []string
+	for _, d := range devices {
+		indices = append(indices, strconv.Itoa(d.Index))
+	}
+	return strings.Join(indices, ",")
+}
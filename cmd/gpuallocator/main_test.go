@@ -0,0 +1,154 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/gpuallocator"
+)
+
+const topoMatrix = `	GPU0	GPU1	GPU2	GPU3	GPU4	GPU5	CPU Affinity	NUMA Affinity
+GPU0	 X 	NV2	NV1	PIX	SYS	SYS	0-7	0
+GPU1	NV2	 X 	NV1	PIX	SYS	SYS	0-7	0
+GPU2	NV1	NV1	 X 	NV2	SYS	SYS	0-7	0
+GPU3	PIX	PIX	NV2	 X 	SYS	SYS	0-7	0
+GPU4	SYS	SYS	SYS	SYS	 X 	NV4	8-15	1
+GPU5	SYS	SYS	SYS	SYS	NV4	 X 	8-15	1
+`
+
+func writeTopologyFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "topology")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func TestRunTopo(t *testing.T) {
+	path := writeTopologyFile(t, topoMatrix)
+
+	for _, format := range []string{"matrix", "json", "dot", "mermaid"} {
+		t.Run(format, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run([]string{"topo", "--topology-file", path, "--format", format}, &out)
+			require.NoError(t, err)
+			require.NotEmpty(t, out.String())
+		})
+	}
+
+	var out bytes.Buffer
+	err := run([]string{"topo", "--topology-file", path, "--format", "yaml"}, &out)
+	require.Error(t, err)
+}
+
+func TestRunSnapshotRoundTrip(t *testing.T) {
+	path := writeTopologyFile(t, topoMatrix)
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+
+	var out bytes.Buffer
+	err := run([]string{"snapshot", "--topology-file", path, "--output", snapshot}, &out)
+	require.NoError(t, err)
+
+	// The snapshot can itself be used as a topology file.
+	var matrix, roundTrip bytes.Buffer
+	require.NoError(t, run([]string{"topo", "--topology-file", path}, &matrix))
+	require.NoError(t, run([]string{"topo", "--topology-file", snapshot}, &roundTrip))
+	require.Equal(t, matrix.String(), roundTrip.String())
+}
+
+func TestRunSnapshotWriteError(t *testing.T) {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full is not available")
+	}
+	path := writeTopologyFile(t, topoMatrix)
+
+	var out bytes.Buffer
+	err := run([]string{"snapshot", "--topology-file", path, "--output", "/dev/full"}, &out)
+	require.Error(t, err)
+}
+
+func TestRunAllocate(t *testing.T) {
+	path := writeTopologyFile(t, topoMatrix)
+
+	var out bytes.Buffer
+	err := run([]string{
+		"allocate", "--topology-file", path,
+		"--policy", "besteffort", "--size", "2",
+		"--required", "0", "--available", "0,1,2,3,5",
+	}, &out)
+	require.NoError(t, err)
+
+	devices, err := gpuallocator.ParseTopoMatrix(bytes.NewReader([]byte(topoMatrix)))
+	require.NoError(t, err)
+	score := gpuallocator.BestEffortSetScore(devices[0:2])
+	require.Contains(t, out.String(), "GPUs: 0,1\n")
+	require.Contains(t, out.String(), fmt.Sprintf("BestEffort score: %d\n", score))
+	require.Contains(t, out.String(), "CUDA_VISIBLE_DEVICES=GPU-0,GPU-1\n")
+}
+
+func TestRunExplain(t *testing.T) {
+	path := writeTopologyFile(t, topoMatrix)
+
+	var out bytes.Buffer
+	err := run([]string{"explain", "--topology-file", path, "--size", "4", "--available", "0,1,2,3"}, &out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "GPU 0 - GPU 1: score ")
+	require.Contains(t, out.String(), "GPU 2 - GPU 3: score ")
+	require.Contains(t, out.String(), "Ring: ")
+}
+
+func TestRunErrors(t *testing.T) {
+	path := writeTopologyFile(t, topoMatrix)
+
+	testCases := []struct {
+		description string
+		args        []string
+	}{
+		{"no command", nil},
+		{"unknown command", []string{"frobnicate"}},
+		{"unknown policy", []string{"allocate", "--topology-file", path, "--policy", "random"}},
+		{"invalid index", []string{"allocate", "--topology-file", path, "--required", "x"}},
+		{"unknown index", []string{"allocate", "--topology-file", path, "--available", "9"}},
+		{"too many GPUs", []string{"allocate", "--topology-file", path, "--size", "7"}},
+		{"missing file", []string{"topo", "--topology-file", filepath.Join(t.TempDir(), "missing")}},
+		{"repair of a file", []string{"topo", "--topology-file", path, "--repair-topology"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var out bytes.Buffer
+			require.Error(t, run(tc.args, &out))
+		})
+	}
+}
+
+func TestTopologyFlagsOptions(t *testing.T) {
+	var topology topologyFlags
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	topology.register(fs)
+	require.Empty(t, topology.options())
+
+	require.NoError(t, fs.Parse([]string{"--sysfs-root", "/host/sys", "--repair-topology"}))
+	require.True(t, topology.repairTopology)
+	require.Len(t, topology.options(), 2)
+}
@@ -0,0 +1,67 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"sort"
+	"strings"
+)
+
+// Allocation holds a set of allocated GPUs in the order they should be
+// presented to applications. Libraries such as NCCL build their rings in the
+// order in which GPUs are listed, so the GPUs are ordered along the ring with
+// the fastest slowest link.
+type Allocation struct {
+	// Devices holds the allocated GPUs in ring order.
+	Devices []*Device
+	// Bottleneck is the pair score of the slowest link along the ring.
+	Bottleneck int
+}
+
+// NewAllocation orders a set of GPUs along the ring through them whose
+// slowest link is as fast as possible. Links are scored from the P2P links
+// in Device.Links. The ring starts at the GPU with the lowest index.
+func NewAllocation(devices []*Device) *Allocation {
+	sorted := append([]*Device{}, devices...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Index < sorted[j].Index
+	})
+
+	ring, bottleneck := bestRing(sorted, calculateGPUPairScore)
+	return &Allocation{
+		Devices:    ring,
+		Bottleneck: bottleneck,
+	}
+}
+
+// CUDAVisibleDevices returns the value of CUDA_VISIBLE_DEVICES that exposes
+// the allocated GPUs in ring order. GPUs are referred to by UUID, since CUDA
+// and NVML may enumerate devices in a different order.
+func (a *Allocation) CUDAVisibleDevices() string {
+	var uuids []string
+	for _, d := range a.Devices {
+		uuids = append(uuids, d.UUID)
+	}
+	return strings.Join(uuids, ",")
+}
+
+// AllocateOrdered allocates a set of 'num' GPUs from the allocator and
+// returns them in ring order.
+// If 'num' devices cannot be allocated, the returned Allocation is empty.
+func (a *Allocator) AllocateOrdered(num int) *Allocation {
+	return NewAllocation(a.Allocate(num))
+}
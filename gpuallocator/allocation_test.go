@@ -0,0 +1,90 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// newNVLinkRingNode creates a node with NVLinks along the ring 0-2-1-3 and
+// slow PCIe links between GPUs 0 and 1 and GPUs 2 and 3.
+func newNVLinkRingNode() TestNode {
+	node := TestNode{
+		NewTestGPU(0),
+		NewTestGPU(1),
+		NewTestGPU(2),
+		NewTestGPU(3),
+	}
+	node.addBidirectionalLink(0, 2, links.TwoNVLINKLinks)
+	node.addBidirectionalLink(2, 1, links.TwoNVLINKLinks)
+	node.addBidirectionalLink(1, 3, links.TwoNVLINKLinks)
+	node.addBidirectionalLink(3, 0, links.TwoNVLINKLinks)
+	node.addBidirectionalLink(0, 1, links.P2PLinkCrossCPU)
+	node.addBidirectionalLink(2, 3, links.P2PLinkCrossCPU)
+	return node
+}
+
+func TestNewAllocation(t *testing.T) {
+	devices := newNVLinkRingNode().Devices()
+
+	testCases := []struct {
+		description string
+		devices     []int
+		order       [][]int
+		bottleneck  int
+	}{
+		{"empty", []int{}, [][]int{nil}, 0},
+		{"single GPU", []int{1}, [][]int{{1}}, 0},
+		{"NVLink pair", []int{2, 1}, [][]int{{1, 2}}, 200},
+		{"PCIe pair", []int{0, 1}, [][]int{{0, 1}}, 10},
+		{"ring follows NVLinks", []int{0, 1, 2, 3}, [][]int{{0, 2, 1, 3}, {0, 3, 1, 2}}, 200},
+		{"input order is ignored", []int{3, 2, 1, 0}, [][]int{{0, 2, 1, 3}, {0, 3, 1, 2}}, 200},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			allocation := NewAllocation(GetDevicesFromIndices(devices, tc.devices))
+			require.Equal(t, tc.bottleneck, allocation.Bottleneck)
+			require.Contains(t, tc.order, indicesOf(allocation.Devices))
+		})
+	}
+}
+
+func TestCUDAVisibleDevices(t *testing.T) {
+	devices := newNVLinkRingNode().Devices()
+
+	allocation := NewAllocation([]*Device{devices[2], devices[0]})
+	require.Equal(t, "GPU-0,GPU-2", allocation.CUDAVisibleDevices())
+
+	require.Equal(t, "", (&Allocation{}).CUDAVisibleDevices())
+}
+
+func TestAllocateOrdered(t *testing.T) {
+	devices := newNVLinkRingNode().Devices()
+	allocator := newAllocatorFrom(devices, NewSimplePolicy())
+
+	allocation := allocator.AllocateOrdered(4)
+	require.Equal(t, 200, allocation.Bottleneck)
+	require.Contains(t, [][]int{{0, 2, 1, 3}, {0, 3, 1, 2}}, indicesOf(allocation.Devices))
+
+	allocation = allocator.AllocateOrdered(1)
+	require.Empty(t, allocation.Devices)
+}
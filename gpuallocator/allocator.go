@@ -14,9 +14,14 @@ import (
 type Allocator struct {
 	GPUs []*Device
 
-	policy    Policy
-	remaining DeviceSet
-	allocated DeviceSet
+	policy     Policy
+	remaining  DeviceSet
+	allocated  DeviceSet
+	owners     map[string]string
+	groups     map[string]string
+	priorities map[string]int
+	cordoned   map[string]bool
+	discover   func() (DeviceList, error)
 }
 
 // Policy defines an interface for pluggable allocation policies to be added
@@ -33,31 +38,39 @@ type Policy interface {
 
 // NewSimpleAllocator creates a new Allocator using the Simple allocation
 // policy
-func NewSimpleAllocator() (*Allocator, error) {
-	return NewAllocator(NewSimplePolicy())
+func NewSimpleAllocator(opts ...Option) (*Allocator, error) {
+	return NewAllocator(NewSimplePolicy(), opts...)
 }
 
 // NewBestEffortAllocator creates a new Allocator using the BestEffort
 // allocation policy
-func NewBestEffortAllocator() (*Allocator, error) {
-	return NewAllocator(NewBestEffortPolicy())
+func NewBestEffortAllocator(opts ...Option) (*Allocator, error) {
+	return NewAllocator(NewBestEffortPolicy(), opts...)
 }
 
-// NewAllocator creates a new Allocator using the given allocation policy
-func NewAllocator(policy Policy) (*Allocator, error) {
+// NewAllocator creates a new Allocator using the given allocation policy. The
+// options are passed to NewDevices() for the initial discovery and for every
+// Rescan(), e.g. WithTopologyRepair() to start on a node with an inconsistent
+// link table.
+func NewAllocator(policy Policy, opts ...Option) (*Allocator, error) {
 	nvmllib := nvml.New()
 	if ret := nvmllib.Init(); ret != nvml.SUCCESS {
 		return nil, fmt.Errorf("error initializing NVML: %v", ret)
 	}
 
-	devices, err := NewDevices(
-		WithNvmlLib(nvmllib),
-	)
+	discover := func() (DeviceList, error) {
+		return NewDevices(
+			append([]Option{WithNvmlLib(nvmllib)}, opts...)...,
+		)
+	}
+
+	devices, err := discover()
 	if err != nil {
 		return nil, fmt.Errorf("error enumerating GPU devices: %v", err)
 	}
 
 	allocator := newAllocatorFrom(devices, policy)
+	allocator.discover = discover
 
 	runtime.SetFinalizer(allocator, func(allocator *Allocator) {
 		// Explicitly ignore any errors from nvml.Shutdown().
@@ -71,10 +84,14 @@ func NewAllocator(policy Policy) (*Allocator, error) {
 // using the supplied set of devices.
 func newAllocatorFrom(devices []*Device, policy Policy) *Allocator {
 	allocator := &Allocator{
-		GPUs:      devices,
-		policy:    policy,
-		remaining: NewDeviceSet(),
-		allocated: NewDeviceSet(),
+		GPUs:       devices,
+		policy:     policy,
+		remaining:  NewDeviceSet(),
+		allocated:  NewDeviceSet(),
+		owners:     make(map[string]string),
+		groups:     make(map[string]string),
+		priorities: make(map[string]int),
+		cordoned:   make(map[string]bool),
 	}
 	allocator.remaining.Insert(devices...)
 	return allocator
@@ -83,9 +100,157 @@ func newAllocatorFrom(devices []*Device, policy Policy) *Allocator {
 // Allocate a set of 'num' GPUs from the allocator.
 // If 'num' devices cannot be allocated, return an empty slice.
 func (a *Allocator) Allocate(num int) []*Device {
-	devices := a.policy.Allocate(a.remaining.SortedSlice(), nil, num)
+	return a.AllocateFor("", num)
+}
+
+// AllocateFor allocates a set of 'num' GPUs from the allocator on behalf of
+// 'owner'. The owner is recorded against each device until it is freed.
+// If 'num' devices cannot be allocated, return an empty slice.
+func (a *Allocator) AllocateFor(owner string, num int) []*Device {
+	devices := a.policy.Allocate(a.available(), nil, num)
+
+	err := a.AllocateSpecificFor(owner, devices...)
+	if err != nil {
+		err = fmt.Errorf("internal error while allocating GPUs: %v", err)
+		panic(err)
+	}
+
+	return devices
+}
+
+// AllocateRequest allocates the GPUs described by 'request' on behalf of its
+// owner. If the request names an owner group and the allocator's policy is a
+// PeerAwarePolicy, the GPUs already allocated to the group are passed to the
+// policy as peers. An error is returned if the request cannot be satisfied.
+func (a *Allocator) AllocateRequest(request Request) ([]*Device, error) {
+	required, err := a.resolveRequired(request.Required)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []*Device
+	if policy, ok := a.policy.(PeerAwarePolicy); ok && request.Group != "" {
+		devices = policy.AllocateWithPeers(a.available(), required, request.Size, a.GroupDevices(request.Group))
+	} else {
+		devices = a.policy.Allocate(a.available(), required, request.Size)
+	}
+	if request.Size <= 0 || len(devices) != request.Size {
+		return nil, fmt.Errorf("unable to allocate %d GPUs", request.Size)
+	}
+
+	if err := a.AllocateSpecificFor(request.Owner, devices...); err != nil {
+		return nil, fmt.Errorf("internal error while allocating GPUs: %v", err)
+	}
+	a.recordRequest(request, devices...)
+
+	return devices, nil
+}
+
+// GroupDevices returns the allocated GPUs whose allocations belong to the
+// specified owner group.
+func (a *Allocator) GroupDevices(group string) []*Device {
+	var devices []*Device
+	for _, gpu := range a.allocated.SortedSlice() {
+		if a.groups[gpu.UUID] == group {
+			devices = append(devices, gpu)
+		}
+	}
+	return devices
+}
+
+// allocationKey identifies an allocation by its request group, or by its
+// owner if it has no group.
+type allocationKey struct {
+	group string
+	owner string
+}
+
+// keyOf returns the key of the allocation holding the GPU.
+func (a *Allocator) keyOf(gpu *Device) allocationKey {
+	if group := a.groups[gpu.UUID]; group != "" {
+		return allocationKey{group: group}
+	}
+	return allocationKey{owner: a.owners[gpu.UUID]}
+}
+
+// String returns the group of the allocation, or its owner if it has no
+// group.
+func (k allocationKey) String() string {
+	if k.group != "" {
+		return k.group
+	}
+	return k.owner
+}
+
+// anonymous returns true if the allocation has neither a group nor an owner.
+func (k allocationKey) anonymous() bool {
+	return k.group == "" && k.owner == ""
+}
+
+// commonOwner returns the owner of the GPUs, or an empty string if they
+// belong to different owners.
+func (a *Allocator) commonOwner(gpus []*Device) string {
+	owner := a.owners[gpus[0].UUID]
+	for _, gpu := range gpus[1:] {
+		if a.owners[gpu.UUID] != owner {
+			return ""
+		}
+	}
+	return owner
+}
 
-	err := a.AllocateSpecific(devices...)
+// recordRequest records the owner group and priority of the request that a
+// set of GPUs was allocated for.
+func (a *Allocator) recordRequest(request Request, devices ...*Device) {
+	for _, gpu := range devices {
+		if request.Group != "" {
+			a.groups[gpu.UUID] = request.Group
+		}
+		if request.Priority != DefaultPriority {
+			a.priorities[gpu.UUID] = request.Priority
+		}
+	}
+}
+
+// resolveRequired returns the allocator's available instances of the
+// required GPUs. An error is returned if any of them is nil or unavailable.
+func (a *Allocator) resolveRequired(devices []*Device) ([]*Device, error) {
+	available := NewDeviceSet(a.available()...)
+	var resolved []*Device
+	for _, gpu := range devices {
+		if gpu == nil {
+			return nil, fmt.Errorf("required device must not be nil")
+		}
+		current, ok := available[gpu.UUID]
+		if !ok {
+			return nil, fmt.Errorf("device '%v' is unavailable for allocation", gpu)
+		}
+		resolved = append(resolved, current)
+	}
+	return resolved, nil
+}
+
+// AllocateWithHints allocates a set of 'num' GPUs that is local to the
+// resources described by 'hints'. If the allocator's policy does not support
+// locality hints, the hints are ignored.
+// If 'num' devices cannot be allocated, return an empty slice.
+func (a *Allocator) AllocateWithHints(num int, hints LocalityHints) []*Device {
+	return a.AllocateWithHintsFor("", num, hints)
+}
+
+// AllocateWithHintsFor allocates a set of 'num' GPUs that is local to the
+// resources described by 'hints' on behalf of 'owner'. If the allocator's
+// policy does not support locality hints, the hints are ignored.
+// If 'num' devices cannot be allocated, return an empty slice.
+func (a *Allocator) AllocateWithHintsFor(owner string, num int, hints LocalityHints) []*Device {
+	policy, ok := a.policy.(LocalityPolicy)
+	if !ok {
+		return a.AllocateFor(owner, num)
+	}
+
+	devices := policy.AllocateWithHints(a.available(), nil, num, hints)
+
+	err := a.AllocateSpecificFor(owner, devices...)
 	if err != nil {
 		err = fmt.Errorf("internal error while allocating GPUs: %v", err)
 		panic(err)
@@ -97,26 +262,74 @@ func (a *Allocator) Allocate(num int) []*Device {
 // AllocateSpecific allocates a specific set of GPUs from the allocator.
 // Return an error if any of the specified devices cannot be allocated.
 func (a *Allocator) AllocateSpecific(devices ...*Device) error {
-	// Make sure we can allocate all of the devices.
+	return a.AllocateSpecificFor("", devices...)
+}
+
+// AllocateSpecificFor allocates a specific set of GPUs from the allocator on
+// behalf of 'owner'. Return an error if any of the specified devices cannot be
+// allocated.
+func (a *Allocator) AllocateSpecificFor(owner string, devices ...*Device) error {
+	// Make sure we can allocate all of the devices. Devices are resolved by
+	// UUID so that references obtained before a Rescan() remain usable.
+	resolved := []*Device{}
 	unavailable := []*Device{}
 	for _, gpu := range devices {
-		if !a.remaining.Contains(gpu) {
+		current, ok := a.remaining[gpu.UUID]
+		if !ok || a.cordoned[gpu.UUID] {
 			unavailable = append(unavailable, gpu)
+			continue
 		}
+		resolved = append(resolved, current)
 	}
 
 	if len(unavailable) != 0 {
-		return fmt.Errorf("devices '%v' are unavailable for allocation, available: %v", unavailable, a.remaining.SortedSlice())
+		return fmt.Errorf("devices '%v' are unavailable for allocation, available: %v", unavailable, a.available())
 	}
 
-	a.allocated.Insert(devices...)
-	a.remaining.Delete(devices...)
+	a.allocated.Insert(resolved...)
+	a.remaining.Delete(resolved...)
+	for _, gpu := range resolved {
+		a.owners[gpu.UUID] = owner
+	}
 
 	return nil
 }
 
-// Free a set of GPUs back to the allocator.
+// Free a set of GPUs back to the allocator. Devices are resolved by UUID, and
+// devices that are not currently allocated, such as GPUs removed by a
+// Rescan(), are ignored.
 func (a *Allocator) Free(devices ...*Device) {
-	a.remaining.Insert(devices...)
-	a.allocated.Delete(devices...)
+	for _, gpu := range devices {
+		gpu, ok := a.allocated[gpu.UUID]
+		if !ok {
+			continue
+		}
+		a.remaining.Insert(gpu)
+		a.allocated.Delete(gpu)
+		delete(a.owners, gpu.UUID)
+		delete(a.groups, gpu.UUID)
+		delete(a.priorities, gpu.UUID)
+	}
+}
+
+// Owner returns the owner recorded for an allocated device and whether the
+// device is currently allocated.
+func (a *Allocator) Owner(device *Device) (string, bool) {
+	if !a.allocated.Contains(device) {
+		return "", false
+	}
+	return a.owners[device.UUID], true
+}
+
+// available returns the sorted list of devices that new allocations may be
+// made from. Cordoned devices are excluded.
+func (a *Allocator) available() []*Device {
+	var available []*Device
+	for _, gpu := range a.remaining.SortedSlice() {
+		if a.cordoned[gpu.UUID] {
+			continue
+		}
+		available = append(available, gpu)
+	}
+	return available
 }
@@ -0,0 +1,150 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// The PCIe link assumed for devices whose link information is unknown.
+const (
+	defaultPCIeGeneration = 3
+	defaultPCIeWidth      = 16
+)
+
+// NewBandwidthAwarePolicy creates a BestEffort policy that scores GPU pairs
+// by their estimated bandwidth (see BandwidthPairScore) and combines them
+// using the specified objective. SetObjectiveSum maximizes the total pairwise
+// bandwidth of a set, while SetObjectiveMinPair maximizes its slowest pair.
+func NewBandwidthAwarePolicy(objective SetObjective) Policy {
+	return NewBestEffortPolicy(
+		WithPairScore(BandwidthPairScore),
+		WithSetObjective(objective),
+	)
+}
+
+// BandwidthPairScore estimates the effective unidirectional bandwidth between
+// two GPUs in MB/s.
+//
+// GPUs connected by NVLinks are scored by the number of links multiplied by
+// the per-link bandwidth of the lowest NVLink version of the two devices.
+// Other pairs are scored by the bandwidth of the slower of their two PCIe
+// links, reduced according to how far apart the GPUs are in the PCIe
+// hierarchy.
+func BandwidthPairScore(gpu0 *Device, gpu1 *Device) int {
+	if gpu0 == nil || gpu1 == nil || gpu0 == gpu1 {
+		return 0
+	}
+
+	nvlinks := 0
+	p2p := links.P2PLinkUnknown
+	for _, link := range gpu0.Links[gpu1.Index] {
+		if link.Type.IsNVLink() {
+			nvlinks = link.Type.NVLinkCount()
+			continue
+		}
+		p2p = link.Type
+	}
+
+	if nvlinks > 0 {
+		version := gpu0.NVLinkVersion
+		if gpu1.NVLinkVersion < version {
+			version = gpu1.NVLinkVersion
+		}
+		return nvlinks * nvlinkBandwidth(version)
+	}
+
+	generation, width := pcieLink(gpu0)
+	generation1, width1 := pcieLink(gpu1)
+	if generation1 < generation {
+		generation = generation1
+	}
+	if width1 < width {
+		width = width1
+	}
+
+	return pcieLaneBandwidth(generation) * width * pcieHopEfficiency(p2p) / 100
+}
+
+// nvlinkBandwidth returns the unidirectional bandwidth of a single NVLink of
+// the specified version in MB/s. Unknown versions are treated as NVLink 2.0.
+func nvlinkBandwidth(version nvml.NvlinkVersion) int {
+	switch version {
+	case nvml.NVLINK_VERSION_1_0:
+		return 20000
+	case nvml.NVLINK_VERSION_5_0:
+		return 50000
+	default:
+		return 25000
+	}
+}
+
+// pcieLaneBandwidth returns the unidirectional bandwidth of a single PCIe
+// lane of the specified generation in MB/s, accounting for line encoding.
+func pcieLaneBandwidth(generation int) int {
+	switch generation {
+	case 1:
+		return 250
+	case 2:
+		return 500
+	case 3:
+		return 985
+	case 4:
+		return 1969
+	case 5:
+		return 3938
+	default:
+		if generation >= 6 {
+			return 7563
+		}
+		return 985
+	}
+}
+
+// pcieHopEfficiency returns the percentage of the PCIe link bandwidth that is
+// expected to be achievable between two GPUs with the specified P2P link.
+func pcieHopEfficiency(p2p links.P2PLinkType) int {
+	switch p2p {
+	case links.P2PLinkSameBoard, links.P2PLinkSingleSwitch:
+		return 100
+	case links.P2PLinkMultiSwitch:
+		return 90
+	case links.P2PLinkHostBridge:
+		return 80
+	case links.P2PLinkSameCPU:
+		return 60
+	case links.P2PLinkCrossCPU:
+		return 40
+	default:
+		return 25
+	}
+}
+
+// pcieLink returns the PCIe generation and width of a device, falling back
+// to the defaults if they are unknown.
+func pcieLink(gpu *Device) (int, int) {
+	generation, width := gpu.PCIeGeneration, gpu.PCIeWidth
+	if generation == 0 {
+		generation = defaultPCIeGeneration
+	}
+	if width == 0 {
+		width = defaultPCIeWidth
+	}
+	return generation, width
+}
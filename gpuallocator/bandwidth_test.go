@@ -0,0 +1,113 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// addBidirectionalLink adds a link of the specified type in both directions
+// between two GPUs of the node.
+func (n TestNode) addBidirectionalLink(gpu0, gpu1 int, linkType links.P2PLinkType) {
+	n.AddLink(gpu0, gpu1, linkType)
+	n.AddLink(gpu1, gpu0, linkType)
+}
+
+func TestBandwidthPairScore(t *testing.T) {
+	dgx1 := NewDGX1VoltaNode().Devices()
+	for _, d := range dgx1 {
+		d.NVLinkVersion = nvml.NVLINK_VERSION_2_0
+	}
+	rtx := New4xRTX8000Node().Devices()
+	rtx[0].PCIeGeneration, rtx[0].PCIeWidth = 4, 16
+	rtx[1].PCIeGeneration, rtx[1].PCIeWidth = 4, 16
+	rtx[2].PCIeGeneration, rtx[2].PCIeWidth = 3, 8
+
+	testCases := []struct {
+		description string
+		gpu0        *Device
+		gpu1        *Device
+		expected    int
+	}{
+		{"single NVLink", dgx1[0], dgx1[1], 25000},
+		{"dual NVLink", dgx1[0], dgx1[3], 50000},
+		{"NVLink uses the lowest version", dgx1[0], withNVLinkVersion(dgx1[3], nvml.NVLINK_VERSION_1_0), 40000},
+		{"PCIe same CPU", rtx[0], rtx[1], 1969 * 16 * 60 / 100},
+		{"PCIe uses the slowest link", rtx[0], rtx[2], 985 * 8 * 40 / 100},
+		{"PCIe link defaults to Gen3 x16", rtx[1], rtx[3], 985 * 16 * 40 / 100},
+		{"same GPU", rtx[0], rtx[0], 0},
+		{"nil GPU", rtx[0], nil, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, BandwidthPairScore(tc.gpu0, tc.gpu1))
+			require.Equal(t, tc.expected, BandwidthPairScore(tc.gpu1, tc.gpu0))
+		})
+	}
+}
+
+func withNVLinkVersion(d *Device, version nvml.NvlinkVersion) *Device {
+	c := *d
+	c.NVLinkVersion = version
+	return &c
+}
+
+func TestBandwidthAwarePolicy(t *testing.T) {
+	node := TestNode{
+		NewTestGPU(0),
+		NewTestGPU(1),
+		NewTestGPU(2),
+		NewTestGPU(3),
+	}
+	node.addBidirectionalLink(0, 1, links.TwelveNVLINKLinks)
+	node.addBidirectionalLink(0, 2, links.TwelveNVLINKLinks)
+	node.addBidirectionalLink(1, 2, links.P2PLinkSameCPU)
+	node.addBidirectionalLink(0, 3, links.ThreeNVLINKLinks)
+	node.addBidirectionalLink(1, 3, links.ThreeNVLINKLinks)
+	node.addBidirectionalLink(2, 3, links.ThreeNVLINKLinks)
+	devices := node.Devices()
+
+	tests := []PolicyAllocTest{
+		{
+			"Sum prefers the set with the highest total bandwidth",
+			devices,
+			[]int{0, 1, 2, 3},
+			[]int{},
+			3,
+			[]int{0, 1, 2},
+		},
+	}
+	RunPolicyAllocTests(t, NewBandwidthAwarePolicy(SetObjectiveSum), tests)
+
+	tests = []PolicyAllocTest{
+		{
+			"Min pair avoids the set with a slow pair",
+			devices,
+			[]int{0, 1, 2, 3},
+			[]int{},
+			3,
+			[]int{0, 1, 3},
+		},
+	}
+	RunPolicyAllocTests(t, NewBandwidthAwarePolicy(SetObjectiveMinPair), tests)
+}
@@ -3,18 +3,67 @@
 package gpuallocator
 
 import (
-	"fmt"
-
 	// TODO: We rename this import to reduce the changes required below.
 	// This can be removed once the link-specifics have been migrated into go-nvlib.
 	nvml "github.com/NVIDIA/go-gpuallocator/internal/links"
 )
 
-type bestEffortPolicy struct{}
+type bestEffortPolicy struct {
+	pairScore PairScoreFunc
+	objective SetObjective
+}
+
+// PairScoreFunc calculates a score for a pair of GPUs. Higher scores indicate
+// GPUs that are better suited to be allocated together.
+type PairScoreFunc func(gpu0 *Device, gpu1 *Device) int
+
+// SetObjective defines how the pair scores within a GPU set are combined
+// into the score of the set.
+type SetObjective int
+
+// The following objectives are supported for scoring GPU sets.
+const (
+	// SetObjectiveSum scores a set by the sum of its pair scores.
+	SetObjectiveSum SetObjective = iota
+	// SetObjectiveMinPair scores a set by its lowest pair score.
+	SetObjectiveMinPair
+	// SetObjectiveRing scores a set by the lowest pair score along the best
+	// ring through all GPUs of the set, as used by ring-based collectives.
+	SetObjectiveRing
+	// SetObjectiveTree scores a set by the lowest pair score of the best
+	// spanning tree through all GPUs of the set, as used by tree-based
+	// collectives.
+	SetObjectiveTree
+)
+
+// BestEffortOption defines a functional option for the BestEffort policy.
+type BestEffortOption func(*bestEffortPolicy)
+
+// WithPairScore sets the function used to score pairs of GPUs.
+func WithPairScore(pairScore PairScoreFunc) BestEffortOption {
+	return func(p *bestEffortPolicy) {
+		p.pairScore = pairScore
+	}
+}
+
+// WithSetObjective sets the objective used to combine pair scores into the
+// score of a GPU set.
+func WithSetObjective(objective SetObjective) BestEffortOption {
+	return func(p *bestEffortPolicy) {
+		p.objective = objective
+	}
+}
 
 // NewBestEffortPolicy creates a new BestEffortPolicy.
-func NewBestEffortPolicy() Policy {
-	return &bestEffortPolicy{}
+func NewBestEffortPolicy(opts ...BestEffortOption) Policy {
+	p := &bestEffortPolicy{
+		pairScore: calculateGPUPairScore,
+		objective: SetObjectiveSum,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // Allocate finds the best set of 'size' GPUs to allocate from a list of
@@ -59,7 +108,7 @@ func (p *bestEffortPolicy) Allocate(available []*Device, required []*Device, siz
 		if !gpuPartitionContainsSetWithAll(candidate, required) {
 			return
 		}
-		score := calculateGPUPartitionScore(candidate)
+		score := p.partitionScore(candidate)
 		if score > bestScore || bestPartition == nil {
 			bestPartition = candidate
 			bestScore = score
@@ -81,9 +130,9 @@ func (p *bestEffortPolicy) Allocate(available []*Device, required []*Device, siz
 
 	// Find the highest scoring GPU set in the highest scoring GPU partition.
 	bestSet := filteredBestPartition[0]
-	bestScore = calculateGPUSetScore(bestSet)
+	bestScore = p.setScore(bestSet)
 	for i := 1; i < len(filteredBestPartition); i++ {
-		score := calculateGPUSetScore(filteredBestPartition[i])
+		score := p.setScore(filteredBestPartition[i])
 		if score > bestScore {
 			bestSet = filteredBestPartition[i]
 			bestScore = score
@@ -295,12 +344,27 @@ func iterateGPUPartitions(devices []*Device, size int, callback func([][]*Device
 	iterate(devices, size, [][]*Device{})
 }
 
+// BestEffortPairScore returns the score assigned to a pair of GPUs by the
+// default BestEffort policy. Higher scores indicate better connected GPUs.
+func BestEffortPairScore(gpu0 *Device, gpu1 *Device) int {
+	return calculateGPUPairScore(gpu0, gpu1)
+}
+
+// BestEffortSetScore returns the score assigned to a set of GPUs by the
+// default BestEffort policy, which is the sum of the scores of all pairs.
+func BestEffortSetScore(gpus []*Device) int {
+	return calculateGPUSetScore(gpus)
+}
+
 // Calculate a "link" score for a pair of GPUs.
 // The score is based on the "closeness" of the two GPUs in relation to one
 // another in terms of the communication links they have with another, as well
 // as the PCIe hierarchy they are in. GPUs connected by an NVLINK receive 100
 // points for each link connecting them. GPUs in the PCIe hierarchy receive
 // points relative to how close they are to one another.
+//
+// If the links reported in each direction differ, the lower of the two scores
+// is used. See DeviceList.ValidateTopology() for detecting such links.
 func calculateGPUPairScore(gpu0 *Device, gpu1 *Device) int {
 	if gpu0 == nil || gpu1 == nil {
 		return 0
@@ -310,14 +374,20 @@ func calculateGPUPairScore(gpu0 *Device, gpu1 *Device) int {
 		return 0
 	}
 
-	if len(gpu0.Links[gpu1.Index]) != len(gpu1.Links[gpu0.Index]) {
-		err := fmt.Errorf("internal error in bestEffort GPU allocator: all P2PLinks between 2 GPUs should be bidirectional")
-		panic(err)
+	score := calculateLinkScore(gpu0.Links[gpu1.Index])
+	if reverse := calculateLinkScore(gpu1.Links[gpu0.Index]); reverse < score {
+		score = reverse
 	}
 
+	return score
+}
+
+// Calculate the score of the links from one GPU to another as used by
+// calculateGPUPairScore.
+func calculateLinkScore(links []P2PLink) int {
 	score := 0
 
-	for _, link := range gpu0.Links[gpu1.Index] {
+	for _, link := range links {
 		switch link.Type {
 		case nvml.P2PLinkCrossCPU:
 			score += 10
@@ -373,6 +443,54 @@ func calculateGPUPairScore(gpu0 *Device, gpu1 *Device) int {
 	return score
 }
 
+// Get the score of a set of GPUs according to the pair score function and set
+// objective of the policy. Padding in the set is ignored.
+func (p *bestEffortPolicy) setScore(gpuSet []*Device) int {
+	var gpus []*Device
+	for _, gpu := range gpuSet {
+		if gpu != nil {
+			gpus = append(gpus, gpu)
+		}
+	}
+
+	switch p.objective {
+	case SetObjectiveRing:
+		_, score := bestRing(gpus, p.pairScore)
+		return score
+	case SetObjectiveTree:
+		return spanningTreeBottleneck(gpus, p.pairScore)
+	case SetObjectiveMinPair:
+		score := 0
+		first := true
+		iterateGPUSets(gpus, 2, func(pair []*Device) {
+			s := p.pairScore(pair[0], pair[1])
+			if first || s < score {
+				score = s
+				first = false
+			}
+		})
+		return score
+	default:
+		score := 0
+		iterateGPUSets(gpus, 2, func(pair []*Device) {
+			score += p.pairScore(pair[0], pair[1])
+		})
+		return score
+	}
+}
+
+// Get the total score of a GPU partition according to the policy. The score
+// is calculated as the sum of the scores of each set within the partition.
+func (p *bestEffortPolicy) partitionScore(gpuPartition [][]*Device) int {
+	score := 0
+
+	for _, gpuSet := range gpuPartition {
+		score += p.setScore(gpuSet)
+	}
+
+	return score
+}
+
 // Get the total score of a set of GPUs. The score is calculated as the sum of
 // the scores calculated for each pair of GPUs in the set.
 func calculateGPUSetScore(gpuSet []*Device) int {
@@ -0,0 +1,92 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import "fmt"
+
+// DrainStatus reports the state of a device that is being taken out of
+// service.
+type DrainStatus struct {
+	Device   *Device
+	Cordoned bool
+	// Allocated is true if the device is still held by an allocation.
+	Allocated bool
+	// Owner is the owner of the allocation still holding the device, if any.
+	Owner string
+}
+
+// Drained returns true if the device is cordoned and no longer allocated.
+func (s DrainStatus) Drained() bool {
+	return s.Cordoned && !s.Allocated
+}
+
+// Cordon marks a device so that new allocations skip it. Allocations that
+// already hold the device are not affected.
+func (a *Allocator) Cordon(device *Device) error {
+	device, err := a.managed(device)
+	if err != nil {
+		return err
+	}
+	a.cordoned[device.UUID] = true
+	return nil
+}
+
+// Uncordon makes a previously cordoned device available to new allocations.
+func (a *Allocator) Uncordon(device *Device) error {
+	device, err := a.managed(device)
+	if err != nil {
+		return err
+	}
+	delete(a.cordoned, device.UUID)
+	return nil
+}
+
+// Cordoned returns true if the device is cordoned by the allocator.
+func (a *Allocator) Cordoned(device *Device) bool {
+	return device != nil && a.cordoned[device.UUID]
+}
+
+// DrainStatus returns the drain status of the specified device.
+func (a *Allocator) DrainStatus(device *Device) (DrainStatus, error) {
+	device, err := a.managed(device)
+	if err != nil {
+		return DrainStatus{}, err
+	}
+	owner, allocated := a.Owner(device)
+	status := DrainStatus{
+		Device:    device,
+		Cordoned:  a.cordoned[device.UUID],
+		Allocated: allocated,
+		Owner:     owner,
+	}
+	return status, nil
+}
+
+// managed returns the allocator's own instance of the specified device.
+// An error is returned if the device is not tracked by the allocator.
+func (a *Allocator) managed(device *Device) (*Device, error) {
+	if device == nil {
+		return nil, fmt.Errorf("device must not be nil")
+	}
+	if d, ok := a.remaining[device.UUID]; ok {
+		return d, nil
+	}
+	if d, ok := a.allocated[device.UUID]; ok {
+		return d, nil
+	}
+	return nil, fmt.Errorf("device %v is not managed by this allocator", device)
+}
@@ -0,0 +1,82 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCordonSkipsDevice(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewSimplePolicy())
+
+	require.NoError(t, allocator.Cordon(devices[0]))
+
+	allocated := allocator.Allocate(2)
+	require.Equal(t, []int{1, 2}, indicesOf(allocated))
+
+	require.Error(t, allocator.AllocateSpecific(devices[0]))
+
+	require.NoError(t, allocator.Uncordon(devices[0]))
+	require.NoError(t, allocator.AllocateSpecific(devices[0]))
+}
+
+func TestCordonIsPerAllocator(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewSimplePolicy())
+	other := newAllocatorFrom(devices, NewSimplePolicy())
+
+	require.NoError(t, allocator.Cordon(devices[0]))
+	require.True(t, allocator.Cordoned(devices[0]))
+	require.False(t, other.Cordoned(devices[0]))
+	require.Equal(t, []int{0, 1}, indicesOf(other.Allocate(2)))
+}
+
+func TestDrainStatus(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewSimplePolicy())
+
+	require.NoError(t, allocator.AllocateSpecificFor("job-a", devices[3]))
+	require.NoError(t, allocator.Cordon(devices[3]))
+
+	status, err := allocator.DrainStatus(devices[3])
+	require.NoError(t, err)
+	require.True(t, status.Cordoned)
+	require.True(t, status.Allocated)
+	require.Equal(t, "job-a", status.Owner)
+	require.False(t, status.Drained())
+
+	allocator.Free(devices[3])
+
+	status, err = allocator.DrainStatus(devices[3])
+	require.NoError(t, err)
+	require.True(t, status.Drained())
+	require.Empty(t, status.Owner)
+
+	_, err = allocator.DrainStatus((*Device)(NewTestGPU(42)))
+	require.Error(t, err)
+}
+
+func indicesOf(devices []*Device) []int {
+	var indices []int
+	for _, d := range devices {
+		indices = append(indices, d.Index)
+	}
+	return indices
+}
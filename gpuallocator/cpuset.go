@@ -0,0 +1,136 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CPUSet holds a sorted set of unique CPU IDs.
+type CPUSet []int
+
+// NewCPUSet creates a CPUSet from the specified CPU IDs.
+func NewCPUSet(cpus ...int) CPUSet {
+	if len(cpus) == 0 {
+		return nil
+	}
+
+	set := append(CPUSet{}, cpus...)
+	sort.Ints(set)
+
+	unique := set[:1]
+	for _, cpu := range set[1:] {
+		if cpu != unique[len(unique)-1] {
+			unique = append(unique, cpu)
+		}
+	}
+	return unique
+}
+
+// ParseCPUSet parses a CPU list in the Linux list format (e.g. 0-3,8,10-11),
+// as used by sysfs local_cpulist files and cpuset cgroups.
+func ParseCPUSet(s string) (CPUSet, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	var cpus []int
+	for _, r := range strings.Split(s, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(r), "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU list %q: %v", s, err)
+		}
+		end := start
+		if len(bounds) == 2 {
+			end, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid CPU list %q: %v", s, err)
+			}
+		}
+		if start < 0 || end < start {
+			return nil, fmt.Errorf("invalid CPU range %q in CPU list %q", r, s)
+		}
+		for cpu := start; cpu <= end; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return NewCPUSet(cpus...), nil
+}
+
+// newCPUSetFromMask creates a CPUSet from a bitmask split into machine words,
+// as returned by the NVML CPU affinity queries.
+func newCPUSetFromMask(mask []uint) CPUSet {
+	var cpus []int
+	for i, word := range mask {
+		for w := uint64(word); w != 0; w &= w - 1 {
+			cpus = append(cpus, i*bits.UintSize+bits.TrailingZeros64(w))
+		}
+	}
+	return NewCPUSet(cpus...)
+}
+
+// Contains checks whether the set contains the specified CPU.
+func (s CPUSet) Contains(cpu int) bool {
+	i := sort.SearchInts(s, cpu)
+	return i < len(s) && s[i] == cpu
+}
+
+// Intersects checks whether the set shares at least one CPU with 'other'.
+func (s CPUSet) Intersects(other CPUSet) bool {
+	for _, cpu := range other {
+		if s.Contains(cpu) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the set in the Linux list format.
+func (s CPUSet) String() string {
+	var ranges []string
+	for i := 0; i < len(s); {
+		j := i
+		for j+1 < len(s) && s[j+1] == s[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(s[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", s[i], s[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ",")
+}
+
+// LocalTo returns the devices whose CPU affinity includes at least one of
+// the specified CPUs.
+func (d DeviceList) LocalTo(cpus CPUSet) DeviceList {
+	var local DeviceList
+	for _, device := range d {
+		if device.CPUs.Intersects(cpus) {
+			local = append(local, device)
+		}
+	}
+	return local
+}
@@ -0,0 +1,71 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCPUSet(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    CPUSet
+		expectedErr bool
+	}{
+		{input: "", expected: nil},
+		{input: "0", expected: CPUSet{0}},
+		{input: "0-3,8,10-11\n", expected: CPUSet{0, 1, 2, 3, 8, 10, 11}},
+		{input: "4,0-1,1", expected: CPUSet{0, 1, 4}},
+		{input: "3-1", expectedErr: true},
+		{input: "a-b", expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			cpus, err := ParseCPUSet(tc.input)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cpus)
+		})
+	}
+}
+
+func TestCPUSet(t *testing.T) {
+	cpus := newCPUSetFromMask([]uint{0xf0f, 0x1})
+	require.Equal(t, "0-3,8-11,64", cpus.String())
+	require.True(t, cpus.Contains(64))
+	require.False(t, cpus.Contains(4))
+	require.True(t, cpus.Intersects(NewCPUSet(5, 11)))
+	require.False(t, cpus.Intersects(NewCPUSet(4, 5)))
+	require.False(t, cpus.Intersects(nil))
+}
+
+func TestDeviceListLocalTo(t *testing.T) {
+	devices := DeviceList(New4xRTX8000Node().Devices())
+	devices[0].CPUs = NewCPUSet(0, 1, 2, 3)
+	devices[1].CPUs = NewCPUSet(0, 1, 2, 3)
+	devices[2].CPUs = NewCPUSet(4, 5, 6, 7)
+
+	require.Equal(t, []int{0, 1}, indicesOf(devices.LocalTo(NewCPUSet(2))))
+	require.Equal(t, []int{0, 1, 2}, indicesOf(devices.LocalTo(NewCPUSet(3, 4))))
+	require.Empty(t, devices.LocalTo(NewCPUSet(8)))
+}
@@ -4,8 +4,11 @@ package gpuallocator
 
 import (
 	"fmt"
+	"runtime"
 	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
@@ -19,6 +22,25 @@ type Device struct {
 	nvlibDevice
 	Index int
 	Links map[int][]P2PLink
+	// Model is the product name of the device as reported by NVML.
+	Model string
+	// CPUs holds the CPUs local to the device. It is empty if the CPU
+	// affinity of the device is unknown.
+	CPUs CPUSet
+	// PCIe points to the device in the PCIe hierarchy discovered from sysfs.
+	// It is nil if the hierarchy could not be discovered.
+	PCIe *links.PCIeNode
+	// NVLinkVersion is the version of the NVLinks of the device. It is
+	// NVLINK_VERSION_INVALID if the device has no active NVLinks.
+	NVLinkVersion nvml.NvlinkVersion
+	// PCIeGeneration and PCIeWidth describe the current PCIe link of the
+	// device as reported by NVML. They are 0 if unknown.
+	PCIeGeneration int
+	PCIeWidth      int
+
+	// sysfs is the sysfs mount the device was discovered from. It is empty
+	// for devices that were not discovered through NVML.
+	sysfs links.Sysfs
 }
 
 type nvlibDevice struct {
@@ -34,7 +56,8 @@ type nvlibDevice struct {
 }
 
 // newDevice constructs a Device for the specified index and nvml Device.
-func newDevice(i int, d device.Device) (*Device, error) {
+// The specified sysfs mount is used to look up NUMA information.
+func newDevice(i int, d device.Device, sysfs links.Sysfs) (*Device, error) {
 	uuid, ret := d.GetUUID()
 	if ret != nvml.SUCCESS {
 		return nil, fmt.Errorf("failed to get device uuid: %v", ret)
@@ -44,20 +67,65 @@ func newDevice(i int, d device.Device) (*Device, error) {
 		return nil, fmt.Errorf("failed to get device pci info: %v", ret)
 	}
 
+	busID := links.PciInfo(pciInfo).BusID()
+
 	device := Device{
 		nvlibDevice: nvlibDevice{
 			Device:      d,
 			UUID:        uuid,
-			PCI:         struct{ BusID string }{BusID: links.PciInfo(pciInfo).BusID()},
-			CPUAffinity: links.PciInfo(pciInfo).CPUAffinity(),
+			PCI:         struct{ BusID string }{BusID: busID},
+			CPUAffinity: sysfs.CPUAffinity(busID),
 		},
 		Index: i,
 		Links: make(map[int][]P2PLink),
+		CPUs:  getCPUAffinity(d, busID, sysfs),
+		sysfs: sysfs,
+	}
+
+	if model, ret := d.GetName(); ret == nvml.SUCCESS {
+		device.Model = model
+	}
+	device.NVLinkVersion = links.GetNVLinkVersion(d)
+	if generation, ret := d.GetCurrPcieLinkGeneration(); ret == nvml.SUCCESS {
+		device.PCIeGeneration = generation
+	}
+	if width, ret := d.GetCurrPcieLinkWidth(); ret == nvml.SUCCESS {
+		device.PCIeWidth = width
 	}
 
 	return &device, nil
 }
 
+// maxCPUs is the number of CPUs requested when querying the CPU affinity of a
+// device from NVML.
+const maxCPUs = 4096
+
+// getCPUAffinity returns the set of CPUs local to a device. NVML is queried
+// for the CPUs on the NUMA node of the device first, followed by its ideal CPU
+// affinity. If neither is available the local_cpulist file in sysfs is used.
+// An empty set is returned if no affinity information is available.
+func getCPUAffinity(d device.Device, busID string, sysfs links.Sysfs) CPUSet {
+	mask, ret := d.GetCpuAffinityWithinScope(maxCPUs, nvml.AFFINITY_SCOPE_NODE)
+	if ret != nvml.SUCCESS {
+		mask, ret = d.GetCpuAffinity(maxCPUs)
+	}
+	if ret == nvml.SUCCESS {
+		if cpus := newCPUSetFromMask(mask); len(cpus) != 0 {
+			return cpus
+		}
+	}
+
+	cpulist, err := sysfs.LocalCPUList(busID)
+	if err != nil {
+		return nil
+	}
+	cpus, err := ParseCPUSet(cpulist)
+	if err != nil {
+		return nil
+	}
+	return cpus
+}
+
 // P2PLink represents a Point-to-Point link between two GPU devices. The link
 // is between the Device struct this struct is embedded in and the GPU Device
 // contained in the P2PLink struct itself.
@@ -84,11 +152,22 @@ func NewDevices(opts ...Option) (DeviceList, error) {
 	if o.devicelib == nil {
 		o.devicelib = device.New(o.nvmllib)
 	}
+	if o.sysfs == "" {
+		o.sysfs = links.DefaultSysfs
+	}
+	if o.concurrency < 1 {
+		o.concurrency = runtime.GOMAXPROCS(0)
+	}
 
 	return o.build()
 }
 
 // build uses the configured options to build a DeviceList.
+//
+// The properties and NVLinks of each device are queried once per device. The
+// links between each pair of devices are then determined from the cached
+// NVLink information and a single topology query per pair. Both steps are
+// spread over at most o.concurrency goroutines.
 func (o *deviceListBuilder) build() (DeviceList, error) {
 	if err := o.nvmllib.Init(); err != nvml.SUCCESS {
 		return nil, fmt.Errorf("error calling nvml.Init: %v", err)
@@ -97,42 +176,95 @@ func (o *deviceListBuilder) build() (DeviceList, error) {
 		_ = o.nvmllib.Shutdown()
 	}()
 
+	start := time.Now()
+	var calls int64
+
 	nvmlDevices, err := o.devicelib.GetDevices()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get devices: %v", err)
 	}
 
-	var devices DeviceList
+	counted := make([]device.Device, len(nvmlDevices))
 	for i, d := range nvmlDevices {
-		device, err := newDevice(i, d)
+		counted[i] = &countingDevice{Device: d, calls: &calls}
+	}
+
+	devices := make(DeviceList, len(nvmlDevices))
+	remotes := make([]links.NVLinkRemotes, len(nvmlDevices))
+	err = parallelize(o.concurrency, len(nvmlDevices), func(i int) error {
+		device, err := newDevice(i, counted[i], o.sysfs)
 		if err != nil {
-			return nil, fmt.Errorf("failed to construct linked device: %v", err)
+			return fmt.Errorf("failed to construct linked device: %v", err)
 		}
-		devices = append(devices, device)
+		// The device is only wrapped for counting during discovery.
+		device.Device = nvmlDevices[i]
+		devices[i] = device
+
+		remotes[i], err = links.GetNVLinkRemotes(counted[i])
+		if err != nil {
+			return fmt.Errorf("error getting NVLinks for device %v: %v", i, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var busIDs []string
+	for _, d := range devices {
+		busIDs = append(busIDs, d.PCI.BusID)
+	}
+	pcieTree := o.sysfs.PCIeTree(busIDs)
+	for _, d := range devices {
+		d.PCIe = pcieTree.Node(d.PCI.BusID)
 	}
 
-	for i, d1 := range nvmlDevices {
-		for j, d2 := range nvmlDevices {
-			if i != j {
-				p2plink, err := links.GetP2PLink(d1, d2)
-				if err != nil {
-					return nil, fmt.Errorf("error getting P2PLink for devices (%v, %v): %v", i, j, err)
-				}
-				if p2plink != links.P2PLinkUnknown {
-					devices[i].Links[j] = append(devices[i].Links[j], P2PLink{devices[j], p2plink})
-				}
-
-				nvlink, err := links.GetNVLink(d1, d2)
-				if err != nil {
-					return nil, fmt.Errorf("error getting NVLink for devices (%v, %v): %v", i, j, err)
-				}
-				if nvlink != links.P2PLinkUnknown {
-					devices[i].Links[j] = append(devices[i].Links[j], P2PLink{devices[j], nvlink})
-				}
+	// Each goroutine only updates the links of device 'i'.
+	err = parallelize(o.concurrency, len(nvmlDevices), func(i int) error {
+		for j := range nvmlDevices {
+			if i == j {
+				continue
 			}
+			p2plink, err := links.GetP2PLink(counted[i], counted[j])
+			if err != nil {
+				return fmt.Errorf("error getting P2PLink for devices (%v, %v): %v", i, j, err)
+			}
+			if p2plink != links.P2PLinkUnknown {
+				devices[i].Links[j] = append(devices[i].Links[j], P2PLink{devices[j], p2plink})
+			}
+
+			nvlink := remotes[i].NVLinkTo(devices[j].PCI.BusID)
+			if nvlink == links.P2PLinkUnknown {
+				nvlink = remotes[i].NVSwitchLinkTo(remotes[j])
+			}
+			if nvlink != links.P2PLinkUnknown {
+				devices[i].Links[j] = append(devices[i].Links[j], P2PLink{devices[j], nvlink})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if o.stats != nil {
+		*o.stats = DiscoveryStats{
+			Devices:   len(devices),
+			NVMLCalls: atomic.LoadInt64(&calls),
+			Duration:  time.Since(start),
 		}
 	}
 
+	if o.repairTopology {
+		devices.RepairTopology()
+	} else if err := devices.ValidateTopology(); err != nil {
+		return nil, fmt.Errorf("error validating GPU topology: %w", err)
+	}
+
+	if o.verification != nil {
+		*o.verification = *devices.Verify(o.expected)
+	}
+
 	return devices, nil
 }
 
@@ -177,8 +309,23 @@ func (d *Device) Details() string {
 	s := ""
 	s += fmt.Sprintf("Device %v:\n", d.Index)
 	s += fmt.Sprintf("  UUID: %v\n", d.UUID)
+	if d.Model != "" {
+		s += fmt.Sprintf("  Model: %v\n", d.Model)
+	}
 	s += fmt.Sprintf("  PCI BusID: %v\n", d.PCI.BusID)
-	s += fmt.Sprintf("  SocketAffinity: %v\n", *d.CPUAffinity)
+	if d.CPUAffinity != nil {
+		s += fmt.Sprintf("  SocketAffinity: %v\n", *d.CPUAffinity)
+	} else {
+		s += "  SocketAffinity: N/A\n"
+	}
+	if len(d.CPUs) != 0 {
+		s += fmt.Sprintf("  CPUAffinity: %v\n", d.CPUs)
+	} else {
+		s += "  CPUAffinity: N/A\n"
+	}
+	if d.PCIe != nil {
+		s += fmt.Sprintf("  PCIe Link: %v (max %v)\n", d.PCIe.CurrentLink, d.PCIe.MaxLink)
+	}
 	s += "  Topology: \n"
 	for gpu, links := range d.Links {
 		s += fmt.Sprintf("    GPU %v Links:\n", gpu)
@@ -17,11 +17,16 @@
 package gpuallocator
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+	"github.com/NVIDIA/go-gpuallocator/internal/sysfstest"
 )
 
 func TestDeviceListFilter(t *testing.T) {
@@ -81,6 +86,162 @@ func TestDeviceListFilter(t *testing.T) {
 	}
 }
 
+func TestNewDeviceCPUAffinity(t *testing.T) {
+	testCases := []struct {
+		description  string
+		scopedMask   []uint
+		scopedReturn nvml.Return
+		mask         []uint
+		maskReturn   nvml.Return
+		expectedCPUs CPUSet
+	}{
+		{
+			description:  "NUMA-scoped affinity",
+			scopedMask:   []uint{0xff},
+			scopedReturn: nvml.SUCCESS,
+			expectedCPUs: CPUSet{0, 1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			description:  "fall back to ideal affinity",
+			scopedReturn: nvml.ERROR_NOT_SUPPORTED,
+			mask:         []uint{0, 0x3},
+			maskReturn:   nvml.SUCCESS,
+			expectedCPUs: CPUSet{64, 65},
+		},
+		{
+			description:  "no affinity information",
+			scopedReturn: nvml.ERROR_NOT_SUPPORTED,
+			maskReturn:   nvml.ERROR_NOT_SUPPORTED,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := newMockDevice(0, "0000:3b:00.0")
+			d.GetCpuAffinityWithinScopeFunc = func(numCPUs int, scope nvml.AffinityScope) ([]uint, nvml.Return) {
+				require.Equal(t, nvml.AffinityScope(nvml.AFFINITY_SCOPE_NODE), scope)
+				return tc.scopedMask, tc.scopedReturn
+			}
+			d.GetCpuAffinityFunc = func(numCPUs int) ([]uint, nvml.Return) {
+				return tc.mask, tc.maskReturn
+			}
+
+			device, err := newDevice(0, nvlibDeviceFrom(t, d), links.Sysfs(t.TempDir()))
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCPUs, device.CPUs)
+			require.NotPanics(t, func() { _ = device.Details() })
+		})
+	}
+}
+
+func TestNewDeviceLinkInfo(t *testing.T) {
+	d := newMockDevice(0, "0000:3b:00.0")
+	d.GetNvLinkStateFunc = func(link int) (nvml.EnableState, nvml.Return) {
+		if link < 2 {
+			return nvml.FEATURE_DISABLED, nvml.SUCCESS
+		}
+		return nvml.FEATURE_ENABLED, nvml.SUCCESS
+	}
+	d.GetNvLinkVersionFunc = func(link int) (uint32, nvml.Return) {
+		require.Equal(t, 2, link)
+		return uint32(nvml.NVLINK_VERSION_4_0), nvml.SUCCESS
+	}
+	d.GetCurrPcieLinkGenerationFunc = func() (int, nvml.Return) {
+		return 5, nvml.SUCCESS
+	}
+	d.GetCurrPcieLinkWidthFunc = func() (int, nvml.Return) {
+		return 16, nvml.SUCCESS
+	}
+
+	device, err := newDevice(0, nvlibDeviceFrom(t, d), links.Sysfs(t.TempDir()))
+	require.NoError(t, err)
+	require.Equal(t, nvml.NVLINK_VERSION_4_0, device.NVLinkVersion)
+	require.Equal(t, 5, device.PCIeGeneration)
+	require.Equal(t, 16, device.PCIeWidth)
+}
+
+func TestNewDevicesWithSysfsRoot(t *testing.T) {
+	sysfs := sysfstest.New(t).
+		AddPCIDevice("0000:00:01.0", "", nil).
+		AddPCIDevice("0000:3b:00.0", "0000:00:01.0", sysfstest.Attributes{
+			"numa_node":     "1",
+			"local_cpulist": "16-31,48-63",
+		})
+
+	nvmllib := newMockNVML(newMockDevice(0, "00000000:3B:00.0"))
+
+	devices, err := NewDevices(WithNvmlLib(nvmllib), WithSysfsRoot(sysfs.Root))
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	require.Equal(t, "0000:3b:00.0", devices[0].PCI.BusID)
+	require.NotNil(t, devices[0].CPUAffinity)
+	require.EqualValues(t, 1, *devices[0].CPUAffinity)
+	require.Equal(t, "16-31,48-63", devices[0].CPUs.String())
+	require.NotNil(t, devices[0].PCIe)
+	require.Equal(t, links.PCIeEndpoint, devices[0].PCIe.Type)
+	require.Equal(t, "0000:00:01.0", devices[0].PCIe.Parent.BusID)
+	require.Nil(t, devices[0].PCIeSwitch())
+}
+
+// newMockDevice creates a mock device with the specified index and bus ID.
+// All optional queries made during device discovery report that they are not
+// supported, and tests can override them as required.
+func newMockDevice(index int, busID string) *mock.Device {
+	return &mock.Device{
+		GetNameFunc: func() (string, nvml.Return) {
+			return fmt.Sprintf("Device%d", index), nvml.SUCCESS
+		},
+		GetUUIDFunc: func() (string, nvml.Return) {
+			return fmt.Sprintf("GPU-%d", index), nvml.SUCCESS
+		},
+		GetPciInfoFunc: func() (nvml.PciInfo, nvml.Return) {
+			var info nvml.PciInfo
+			copy(info.BusId[:], busID)
+			return info, nvml.SUCCESS
+		},
+		GetCpuAffinityWithinScopeFunc: func(int, nvml.AffinityScope) ([]uint, nvml.Return) {
+			return nil, nvml.ERROR_NOT_SUPPORTED
+		},
+		GetCpuAffinityFunc: func(int) ([]uint, nvml.Return) {
+			return nil, nvml.ERROR_NOT_SUPPORTED
+		},
+		GetNvLinkStateFunc: func(int) (nvml.EnableState, nvml.Return) {
+			return nvml.FEATURE_DISABLED, nvml.ERROR_NOT_SUPPORTED
+		},
+		GetCurrPcieLinkGenerationFunc: func() (int, nvml.Return) {
+			return 0, nvml.ERROR_NOT_SUPPORTED
+		},
+		GetCurrPcieLinkWidthFunc: func() (int, nvml.Return) {
+			return 0, nvml.ERROR_NOT_SUPPORTED
+		},
+	}
+}
+
+// newMockNVML creates a mock NVML library that reports the specified devices.
+func newMockNVML(devices ...*mock.Device) *mock.Interface {
+	return &mock.Interface{
+		InitFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		ShutdownFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		DeviceGetCountFunc: func() (int, nvml.Return) {
+			return len(devices), nvml.SUCCESS
+		},
+		DeviceGetHandleByIndexFunc: func(index int) (nvml.Device, nvml.Return) {
+			return devices[index], nvml.SUCCESS
+		},
+	}
+}
+
+// nvlibDeviceFrom wraps an nvml.Device as a go-nvlib device.
+func nvlibDeviceFrom(t *testing.T, d nvml.Device) device.Device {
+	nvlibDevice, err := device.New(&mock.Interface{}).NewDevice(d)
+	require.NoError(t, err)
+	return nvlibDevice
+}
+
 func setNVMLNewDuringTest(to nvml.Interface) func() {
 	original := nvmlNew
 	nvmlNew = func() nvml.Interface {
@@ -0,0 +1,285 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// MatchedDevice holds a device that is present in both lists compared by
+// DiffTopology.
+type MatchedDevice struct {
+	Old *Device
+	New *Device
+}
+
+// String returns the index of the device, including its new index if the
+// device was renumbered.
+func (m MatchedDevice) String() string {
+	if m.Old.Index == m.New.Index {
+		return fmt.Sprintf("GPU %d", m.Old.Index)
+	}
+	return fmt.Sprintf("GPU %d (now GPU %d)", m.Old.Index, m.New.Index)
+}
+
+// NUMAChange describes a device whose NUMA affinity changed. A nil node
+// indicates that the NUMA affinity is unknown.
+type NUMAChange struct {
+	MatchedDevice
+	OldNode *uint
+	NewNode *uint
+}
+
+// LinkChange describes a pair of devices whose links changed.
+type LinkChange struct {
+	GPU0 MatchedDevice
+	GPU1 MatchedDevice
+	Old  []links.P2PLinkType
+	New  []links.P2PLinkType
+}
+
+// LostNVLinks returns the number of NVLinks between the pair of devices that
+// are no longer present.
+func (c LinkChange) LostNVLinks() int {
+	lost := nvlinkCount(c.Old) - nvlinkCount(c.New)
+	if lost < 0 {
+		return 0
+	}
+	return lost
+}
+
+// TopologyDiff holds the differences between two device lists.
+type TopologyDiff struct {
+	// Added holds the devices only present in the new list.
+	Added []*Device
+	// Removed holds the devices only present in the old list.
+	Removed []*Device
+	// Replaced holds the devices matched by bus ID whose UUID changed, such
+	// as after a GPU was swapped.
+	Replaced []MatchedDevice
+	// NUMAChanges holds the devices whose NUMA affinity changed.
+	NUMAChanges []NUMAChange
+	// LinkChanges holds the pairs of devices whose links changed.
+	LinkChanges []LinkChange
+}
+
+// DiffTopology compares two device lists, such as a snapshot and a live list,
+// or the lists of two nodes. Changes are reported from 'from' to 'to'. Devices
+// are matched by UUID, and the remaining devices are matched by PCI bus ID. The
+// links are compared for each pair of matched devices.
+func DiffTopology(from, to DeviceList) *TopologyDiff {
+	diff := &TopologyDiff{}
+
+	matched := matchDevices(from, to)
+	for _, gpu := range from {
+		if _, ok := matched[gpu]; !ok {
+			diff.Removed = append(diff.Removed, gpu)
+		}
+	}
+	matchedNew := make(map[*Device]bool)
+	for _, m := range matched {
+		matchedNew[m.New] = true
+	}
+	for _, gpu := range to {
+		if !matchedNew[gpu] {
+			diff.Added = append(diff.Added, gpu)
+		}
+	}
+
+	var pairs []MatchedDevice
+	for _, gpu := range from {
+		m, ok := matched[gpu]
+		if !ok {
+			continue
+		}
+		pairs = append(pairs, m)
+		if m.Old.UUID != m.New.UUID {
+			diff.Replaced = append(diff.Replaced, m)
+		}
+		if !sameNUMANode(m.Old.CPUAffinity, m.New.CPUAffinity) {
+			diff.NUMAChanges = append(diff.NUMAChanges, NUMAChange{m, m.Old.CPUAffinity, m.New.CPUAffinity})
+		}
+	}
+
+	for i, gpu0 := range pairs {
+		for _, gpu1 := range pairs[i+1:] {
+			oldLinks := sortedLinkTypes(gpu0.Old.Links[gpu1.Old.Index])
+			newLinks := sortedLinkTypes(gpu0.New.Links[gpu1.New.Index])
+			if !equalLinkTypes(oldLinks, newLinks) {
+				diff.LinkChanges = append(diff.LinkChanges, LinkChange{gpu0, gpu1, oldLinks, newLinks})
+			}
+		}
+	}
+
+	return diff
+}
+
+// matchDevices matches the devices of one list to those of another, first by
+// UUID and then by PCI bus ID. The result is keyed by the devices of the first
+// list.
+func matchDevices(from, to DeviceList) map[*Device]MatchedDevice {
+	matched := make(map[*Device]MatchedDevice)
+	used := make(map[*Device]bool)
+
+	byUUID := make(map[string]*Device)
+	for _, gpu := range to {
+		byUUID[gpu.UUID] = gpu
+	}
+	for _, gpu := range from {
+		if peer, ok := byUUID[gpu.UUID]; ok && !used[peer] {
+			matched[gpu] = MatchedDevice{gpu, peer}
+			used[peer] = true
+		}
+	}
+
+	byBusID := make(map[string]*Device)
+	for _, gpu := range to {
+		if !used[gpu] && gpu.PCI.BusID != "" {
+			byBusID[gpu.PCI.BusID] = gpu
+		}
+	}
+	for _, gpu := range from {
+		if _, ok := matched[gpu]; ok || gpu.PCI.BusID == "" {
+			continue
+		}
+		if peer, ok := byBusID[gpu.PCI.BusID]; ok {
+			matched[gpu] = MatchedDevice{gpu, peer}
+			delete(byBusID, gpu.PCI.BusID)
+		}
+	}
+
+	return matched
+}
+
+func sameNUMANode(node0, node1 *uint) bool {
+	if node0 == nil || node1 == nil {
+		return node0 == node1
+	}
+	return *node0 == *node1
+}
+
+func sortedLinkTypes(p2pLinks []P2PLink) []links.P2PLinkType {
+	var types []links.P2PLinkType
+	for _, link := range p2pLinks {
+		types = append(types, link.Type)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	return types
+}
+
+func equalLinkTypes(types0, types1 []links.P2PLinkType) bool {
+	if len(types0) != len(types1) {
+		return false
+	}
+	for i := range types0 {
+		if types0[i] != types1[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func nvlinkCount(types []links.P2PLinkType) int {
+	count := 0
+	for _, t := range types {
+		count += t.NVLinkCount()
+	}
+	return count
+}
+
+// IsEmpty returns true if the device lists compared have the same topology.
+func (d *TopologyDiff) IsEmpty() bool {
+	return len(d.Added) == 0 &&
+		len(d.Removed) == 0 &&
+		len(d.Replaced) == 0 &&
+		len(d.NUMAChanges) == 0 &&
+		len(d.LinkChanges) == 0
+}
+
+// String returns a human-readable report of the differences.
+func (d *TopologyDiff) String() string {
+	if d.IsEmpty() {
+		return "No topology changes"
+	}
+
+	s := ""
+	if len(d.Added) != 0 {
+		s += "Added GPUs:\n"
+		for _, gpu := range d.Added {
+			s += fmt.Sprintf("  GPU %d: %v (%v)\n", gpu.Index, gpu.UUID, gpu.PCI.BusID)
+		}
+	}
+	if len(d.Removed) != 0 {
+		s += "Removed GPUs:\n"
+		for _, gpu := range d.Removed {
+			s += fmt.Sprintf("  GPU %d: %v (%v)\n", gpu.Index, gpu.UUID, gpu.PCI.BusID)
+		}
+	}
+	if len(d.Replaced) != 0 {
+		s += "Replaced GPUs:\n"
+		for _, m := range d.Replaced {
+			s += fmt.Sprintf("  %v: %v -> %v (%v)\n", m, m.Old.UUID, m.New.UUID, m.New.PCI.BusID)
+		}
+	}
+	if len(d.NUMAChanges) != 0 {
+		s += "NUMA affinity changes:\n"
+		for _, c := range d.NUMAChanges {
+			s += fmt.Sprintf("  %v: %v -> %v\n", c.MatchedDevice, numaNodeString(c.OldNode), numaNodeString(c.NewNode))
+		}
+	}
+	if len(d.LinkChanges) != 0 {
+		s += "Link changes:\n"
+		for _, c := range d.LinkChanges {
+			s += fmt.Sprintf("  %v - %v: %v -> %v", c.GPU0, c.GPU1, linkTypesString(c.Old), linkTypesString(c.New))
+			switch lost := c.LostNVLinks(); {
+			case lost == 1:
+				s += " (lost 1 NVLink)"
+			case lost > 1:
+				s += fmt.Sprintf(" (lost %d NVLinks)", lost)
+			}
+			s += "\n"
+		}
+	}
+
+	return strings.TrimSuffix(s, "\n")
+}
+
+func numaNodeString(node *uint) string {
+	if node == nil {
+		return "N/A"
+	}
+	return fmt.Sprintf("%d", *node)
+}
+
+// linkTypesString returns the link types in the abbreviated form used by
+// 'nvidia-smi topo -m', joined by '+'.
+func linkTypesString(types []links.P2PLinkType) string {
+	if len(types) == 0 {
+		return "none"
+	}
+	var abbreviations []string
+	for _, t := range types {
+		abbreviations = append(abbreviations, t.Abbreviation())
+	}
+	return strings.Join(abbreviations, "+")
+}
@@ -0,0 +1,119 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+func TestDiffTopologyUnchanged(t *testing.T) {
+	diff := DiffTopology(newTwoSocketNode(), newTwoSocketNode())
+	require.True(t, diff.IsEmpty())
+	require.Equal(t, "No topology changes", diff.String())
+}
+
+func TestDiffTopology(t *testing.T) {
+	from := newTwoSocketNode()
+
+	// GPU 0 and GPU 1 lost an NVLink, GPU 1 moved to NUMA node 1, GPU 2 was
+	// swapped for a new GPU in the same slot, and a new GPU 3 was added.
+	node := TestNode{
+		NewTestGPU(0),
+		NewTestGPU(1),
+		NewTestGPU(2),
+		NewTestGPU(3),
+	}.setNUMANodes(0, 1, 1, 1)
+	node[2].UUID = "GPU-new"
+	node.addBidirectionalLink(0, 1, links.P2PLinkSameCPU)
+	node.addBidirectionalLink(0, 1, links.SingleNVLINKLink)
+	node.addBidirectionalLink(0, 2, links.P2PLinkCrossCPU)
+	node.addBidirectionalLink(1, 2, links.P2PLinkCrossCPU)
+	node.addBidirectionalLink(0, 3, links.P2PLinkCrossCPU)
+	to := DeviceList(node.Devices())
+
+	diff := DiffTopology(from, to)
+	require.False(t, diff.IsEmpty())
+
+	require.Equal(t, []*Device{to[3]}, diff.Added)
+	require.Empty(t, diff.Removed)
+	require.Equal(t, []MatchedDevice{{from[2], to[2]}}, diff.Replaced)
+
+	require.Len(t, diff.NUMAChanges, 1)
+	require.Equal(t, MatchedDevice{from[1], to[1]}, diff.NUMAChanges[0].MatchedDevice)
+	require.EqualValues(t, 0, *diff.NUMAChanges[0].OldNode)
+	require.EqualValues(t, 1, *diff.NUMAChanges[0].NewNode)
+
+	require.Len(t, diff.LinkChanges, 1)
+	change := diff.LinkChanges[0]
+	require.Equal(t, MatchedDevice{from[0], to[0]}, change.GPU0)
+	require.Equal(t, MatchedDevice{from[1], to[1]}, change.GPU1)
+	require.Equal(t, []links.P2PLinkType{links.P2PLinkSameCPU, links.TwoNVLINKLinks}, change.Old)
+	require.Equal(t, []links.P2PLinkType{links.P2PLinkSameCPU, links.SingleNVLINKLink}, change.New)
+	require.Equal(t, 1, change.LostNVLinks())
+
+	expected := `Added GPUs:
+  GPU 3: GPU-3 (GPU-3)
+Replaced GPUs:
+  GPU 2: GPU-2 -> GPU-new (GPU-2)
+NUMA affinity changes:
+  GPU 1: 0 -> 1
+Link changes:
+  GPU 0 - GPU 1: NODE+NV2 -> NODE+NV1 (lost 1 NVLink)`
+	require.Equal(t, expected, diff.String())
+}
+
+func TestDiffTopologyRemovedAndRenumbered(t *testing.T) {
+	from := newTwoSocketNode()
+
+	// GPU 0 fell off the bus, so the remaining GPUs are renumbered.
+	node := TestNode{
+		NewTestGPU(0),
+		NewTestGPU(1),
+	}.setNUMANodes(0, 1)
+	node[0].UUID, node[0].PCI.BusID = "GPU-1", "GPU-1"
+	node[1].UUID, node[1].PCI.BusID = "GPU-2", "GPU-2"
+	node.addBidirectionalLink(0, 1, links.P2PLinkCrossCPU)
+	to := DeviceList(node.Devices())
+
+	diff := DiffTopology(from, to)
+	require.Equal(t, []*Device{from[0]}, diff.Removed)
+	require.Empty(t, diff.Added)
+	require.Empty(t, diff.Replaced)
+	require.Empty(t, diff.NUMAChanges)
+	require.Empty(t, diff.LinkChanges)
+
+	expected := `Removed GPUs:
+  GPU 0: GPU-0 (GPU-0)`
+	require.Equal(t, expected, diff.String())
+}
+
+func TestDiffTopologyLostAllNVLinks(t *testing.T) {
+	from := DeviceList(NewDGX1VoltaNode().Devices())
+	node := NewDGX1VoltaNode()
+	delete(node[0].Links, 3)
+	delete(node[3].Links, 0)
+	to := DeviceList(node.Devices())
+
+	diff := DiffTopology(from, to)
+	require.Len(t, diff.LinkChanges, 1)
+	require.Equal(t, 2, diff.LinkChanges[0].LostNVLinks())
+	require.Equal(t, "Link changes:\n  GPU 0 - GPU 3: PHB+NV2 -> none (lost 2 NVLinks)", diff.String())
+}
@@ -0,0 +1,151 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// DiscoveryStats reports statistics about the discovery of a list of devices.
+type DiscoveryStats struct {
+	// Devices is the number of devices discovered.
+	Devices int
+	// NVMLCalls is the number of NVML device queries made to discover the
+	// properties and links of the devices.
+	NVMLCalls int64
+	// Duration is the time taken by the discovery.
+	Duration time.Duration
+}
+
+// parallelize calls fn for each index in [0, n) using at most 'workers'
+// concurrent goroutines. If any call fails, the error for the lowest index is
+// returned.
+func parallelize(workers int, n int, fn func(i int) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	errs := make([]error, n)
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countingDevice wraps a device and counts the NVML queries made during
+// discovery. Only the queries used by discovery are counted.
+type countingDevice struct {
+	device.Device
+	calls *int64
+}
+
+func (d *countingDevice) count() {
+	atomic.AddInt64(d.calls, 1)
+}
+
+func (d *countingDevice) GetUUID() (string, nvml.Return) {
+	d.count()
+	return d.Device.GetUUID()
+}
+
+func (d *countingDevice) GetName() (string, nvml.Return) {
+	d.count()
+	return d.Device.GetName()
+}
+
+func (d *countingDevice) GetPciInfo() (nvml.PciInfo, nvml.Return) {
+	d.count()
+	return d.Device.GetPciInfo()
+}
+
+func (d *countingDevice) GetCpuAffinityWithinScope(numCPUs int, scope nvml.AffinityScope) ([]uint, nvml.Return) {
+	d.count()
+	return d.Device.GetCpuAffinityWithinScope(numCPUs, scope)
+}
+
+func (d *countingDevice) GetCpuAffinity(numCPUs int) ([]uint, nvml.Return) {
+	d.count()
+	return d.Device.GetCpuAffinity(numCPUs)
+}
+
+func (d *countingDevice) GetNvLinkState(link int) (nvml.EnableState, nvml.Return) {
+	d.count()
+	return d.Device.GetNvLinkState(link)
+}
+
+func (d *countingDevice) GetNvLinkVersion(link int) (uint32, nvml.Return) {
+	d.count()
+	return d.Device.GetNvLinkVersion(link)
+}
+
+func (d *countingDevice) GetNvLinkRemoteDeviceType(link int) (nvml.IntNvLinkDeviceType, nvml.Return) {
+	d.count()
+	return d.Device.GetNvLinkRemoteDeviceType(link)
+}
+
+func (d *countingDevice) GetNvLinkRemotePciInfo(link int) (nvml.PciInfo, nvml.Return) {
+	d.count()
+	return d.Device.GetNvLinkRemotePciInfo(link)
+}
+
+func (d *countingDevice) GetCurrPcieLinkGeneration() (int, nvml.Return) {
+	d.count()
+	return d.Device.GetCurrPcieLinkGeneration()
+}
+
+func (d *countingDevice) GetCurrPcieLinkWidth() (int, nvml.Return) {
+	d.count()
+	return d.Device.GetCurrPcieLinkWidth()
+}
+
+// GetTopologyCommonAncestor unwraps the peer device, since NVML needs to
+// resolve its handle.
+func (d *countingDevice) GetTopologyCommonAncestor(peer nvml.Device) (nvml.GpuTopologyLevel, nvml.Return) {
+	d.count()
+	if c, ok := peer.(*countingDevice); ok {
+		peer = c.Device
+	}
+	return d.Device.GetTopologyCommonAncestor(peer)
+}
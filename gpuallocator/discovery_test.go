@@ -0,0 +1,260 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// newFullyConnectedMockDevices creates 'n' mock devices where NVLink 'j' of
+// each device connects it to device 'j' (or 'j+1' for links beyond its own
+// index), and all devices are on different CPUs.
+func newFullyConnectedMockDevices(n int) []*mock.Device {
+	busID := func(i int) string {
+		return fmt.Sprintf("00000000:%02X:00.0", 0x10+i)
+	}
+
+	var devices []*mock.Device
+	for i := 0; i < n; i++ {
+		i := i
+		d := newMockDevice(i, busID(i))
+		d.GetNvLinkStateFunc = func(link int) (nvml.EnableState, nvml.Return) {
+			if link < n-1 {
+				return nvml.FEATURE_ENABLED, nvml.SUCCESS
+			}
+			return nvml.FEATURE_DISABLED, nvml.SUCCESS
+		}
+		d.GetNvLinkVersionFunc = func(int) (uint32, nvml.Return) {
+			return uint32(nvml.NVLINK_VERSION_3_0), nvml.SUCCESS
+		}
+		d.GetNvLinkRemoteDeviceTypeFunc = func(int) (nvml.IntNvLinkDeviceType, nvml.Return) {
+			return nvml.NVLINK_DEVICE_TYPE_GPU, nvml.SUCCESS
+		}
+		d.GetNvLinkRemotePciInfoFunc = func(link int) (nvml.PciInfo, nvml.Return) {
+			peer := link
+			if peer >= i {
+				peer++
+			}
+			var info nvml.PciInfo
+			copy(info.BusId[:], busID(peer))
+			return info, nvml.SUCCESS
+		}
+		d.GetTopologyCommonAncestorFunc = func(nvml.Device) (nvml.GpuTopologyLevel, nvml.Return) {
+			return nvml.TOPOLOGY_SYSTEM, nvml.SUCCESS
+		}
+		devices = append(devices, d)
+	}
+	return devices
+}
+
+// newNVSwitchMockDevices creates 'n' mock devices of the specified model where
+// the first 'nvlinks' NVLinks of each device are attached to NVSwitches, and
+// all devices are on different CPUs.
+func newNVSwitchMockDevices(n int, nvlinks int, model string) []*mock.Device {
+	var devices []*mock.Device
+	for i := 0; i < n; i++ {
+		d := newMockDevice(i, fmt.Sprintf("00000000:%02X:00.0", 0x10+i))
+		d.GetNameFunc = func() (string, nvml.Return) {
+			return model, nvml.SUCCESS
+		}
+		d.GetNvLinkStateFunc = func(link int) (nvml.EnableState, nvml.Return) {
+			if link < nvlinks {
+				return nvml.FEATURE_ENABLED, nvml.SUCCESS
+			}
+			return nvml.FEATURE_DISABLED, nvml.SUCCESS
+		}
+		d.GetNvLinkVersionFunc = func(int) (uint32, nvml.Return) {
+			return uint32(nvml.NVLINK_VERSION_3_0), nvml.SUCCESS
+		}
+		d.GetNvLinkRemoteDeviceTypeFunc = func(int) (nvml.IntNvLinkDeviceType, nvml.Return) {
+			return nvml.NVLINK_DEVICE_TYPE_SWITCH, nvml.SUCCESS
+		}
+		d.GetTopologyCommonAncestorFunc = func(nvml.Device) (nvml.GpuTopologyLevel, nvml.Return) {
+			return nvml.TOPOLOGY_SYSTEM, nvml.SUCCESS
+		}
+		devices = append(devices, d)
+	}
+	return devices
+}
+
+// countedCalls returns the number of calls made to the device queries counted
+// during discovery. The name of each device is also queried once by go-nvlib
+// when enumerating devices, which is not part of the count.
+func countedCalls(d *mock.Device) int64 {
+	return int64(len(d.GetUUIDCalls()) +
+		len(d.GetNameCalls()) - 1 +
+		len(d.GetPciInfoCalls()) +
+		len(d.GetCpuAffinityWithinScopeCalls()) +
+		len(d.GetCpuAffinityCalls()) +
+		len(d.GetNvLinkStateCalls()) +
+		len(d.GetNvLinkVersionCalls()) +
+		len(d.GetNvLinkRemoteDeviceTypeCalls()) +
+		len(d.GetNvLinkRemotePciInfoCalls()) +
+		len(d.GetCurrPcieLinkGenerationCalls()) +
+		len(d.GetCurrPcieLinkWidthCalls()) +
+		len(d.GetTopologyCommonAncestorCalls()))
+}
+
+func TestNewDevicesDiscovery(t *testing.T) {
+	for _, n := range []int{2, 4, 8} {
+		t.Run(fmt.Sprintf("%d devices", n), func(t *testing.T) {
+			mocks := newFullyConnectedMockDevices(n)
+
+			var stats DiscoveryStats
+			devices, err := NewDevices(
+				WithNvmlLib(newMockNVML(mocks...)),
+				WithSysfsRoot(t.TempDir()),
+				WithDiscoveryConcurrency(3),
+				WithDiscoveryStats(&stats),
+			)
+			require.NoError(t, err)
+			require.Len(t, devices, n)
+
+			for i, d := range devices {
+				require.Equal(t, i, d.Index)
+				require.Equal(t, nvml.NVLINK_VERSION_3_0, d.NVLinkVersion)
+				require.Len(t, d.Links, n-1)
+				for j, peerLinks := range d.Links {
+					require.ElementsMatch(t, []P2PLink{
+						{devices[j], links.P2PLinkCrossCPU},
+						{devices[j], links.SingleNVLINKLink},
+					}, peerLinks)
+				}
+			}
+
+			// NVLinks are queried a fixed number of times per device,
+			// independent of the number of peers.
+			var calls int64
+			for _, d := range mocks {
+				require.Len(t, d.GetNvLinkStateCalls(), nvml.NVLINK_MAX_LINKS+1)
+				require.Len(t, d.GetNvLinkRemotePciInfoCalls(), n-1)
+				require.Len(t, d.GetTopologyCommonAncestorCalls(), n-1)
+				calls += countedCalls(d)
+			}
+
+			require.Equal(t, n, stats.Devices)
+			require.Equal(t, calls, stats.NVMLCalls)
+		})
+	}
+}
+
+func TestNewDevicesNVSwitch(t *testing.T) {
+	testCases := []struct {
+		system  string
+		gpus    int
+		nvlinks int
+		model   string
+	}{
+		{"DGX-2", 16, 6, "Tesla V100-SXM3-32GB"},
+		{"DGX A100", 8, 12, "NVIDIA A100-SXM4-80GB"},
+		{"HGX H100", 8, 18, "NVIDIA H100 80GB HBM3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.system, func(t *testing.T) {
+			mocks := newNVSwitchMockDevices(tc.gpus, tc.nvlinks, tc.model)
+			devices, err := NewDevices(WithNvmlLib(newMockNVML(mocks...)), WithSysfsRoot(t.TempDir()))
+			require.NoError(t, err)
+
+			for _, d := range devices {
+				require.Len(t, d.Links, tc.gpus-1)
+				for j, peerLinks := range d.Links {
+					require.ElementsMatch(t, []P2PLink{
+						{devices[j], links.P2PLinkCrossCPU},
+						{devices[j], links.SingleNVLINKLink + links.P2PLinkType(tc.nvlinks-1)},
+					}, peerLinks)
+				}
+			}
+			for _, d := range mocks {
+				require.Empty(t, d.GetNvLinkRemotePciInfoCalls())
+			}
+
+			system, found := IdentifySystem(devices)
+			require.True(t, found)
+			require.Equal(t, tc.system, system.Name)
+		})
+	}
+}
+
+func TestNVSwitchPairScores(t *testing.T) {
+	// GPUs 0-3 and GPUs 4-7 are attached to different CPUs.
+	mocks := newNVSwitchMockDevices(8, 12, "NVIDIA A100-SXM4-80GB")
+	for i, d := range mocks {
+		i := i
+		d.GetTopologyCommonAncestorFunc = func(peer nvml.Device) (nvml.GpuTopologyLevel, nvml.Return) {
+			uuid, _ := peer.GetUUID()
+			var j int
+			_, _ = fmt.Sscanf(uuid, "GPU-%d", &j)
+			if i/4 == j/4 {
+				return nvml.TOPOLOGY_NODE, nvml.SUCCESS
+			}
+			return nvml.TOPOLOGY_SYSTEM, nvml.SUCCESS
+		}
+	}
+
+	devices, err := NewDevices(WithNvmlLib(newMockNVML(mocks...)), WithSysfsRoot(t.TempDir()))
+	require.NoError(t, err)
+
+	// Pairs on the NVSwitch fabric score their NVLinks, and the PCIe links
+	// only break ties between them.
+	require.Equal(t, 1220, BestEffortPairScore(devices[0], devices[1]))
+	require.Equal(t, 1210, BestEffortPairScore(devices[0], devices[4]))
+
+	allocated := NewBestEffortPolicy().Allocate(devices, nil, 4)
+	require.ElementsMatch(t, []int{0, 1, 2, 3}, indicesOf(allocated))
+	allocated = NewBestEffortPolicy().Allocate(devices, []*Device{devices[5]}, 2)
+	require.ElementsMatch(t, []int{4, 5}, indicesOf(allocated))
+}
+
+func TestNewDevicesDiscoveryError(t *testing.T) {
+	mocks := newFullyConnectedMockDevices(4)
+	mocks[2].GetTopologyCommonAncestorFunc = func(nvml.Device) (nvml.GpuTopologyLevel, nvml.Return) {
+		return 0, nvml.ERROR_UNKNOWN
+	}
+
+	_, err := NewDevices(
+		WithNvmlLib(newMockNVML(mocks...)),
+		WithSysfsRoot(t.TempDir()),
+	)
+	require.ErrorContains(t, err, "error getting P2PLink for devices (2, 0)")
+}
+
+func TestParallelize(t *testing.T) {
+	for _, workers := range []int{0, 1, 4, 100} {
+		visited := make([]bool, 10)
+		err := parallelize(workers, len(visited), func(i int) error {
+			visited[i] = true
+			if i >= 5 {
+				return fmt.Errorf("error %d", i)
+			}
+			return nil
+		})
+		require.EqualError(t, err, "error 5")
+		for _, v := range visited {
+			require.True(t, v)
+		}
+	}
+
+	require.NoError(t, parallelize(4, 0, func(int) error { return nil }))
+}
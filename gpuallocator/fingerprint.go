@@ -0,0 +1,65 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fingerprint returns a hash of the GPU topology of the devices. It covers
+// the model of each GPU and the links between each pair of GPUs as shown by
+// 'nvidia-smi topo -m'. The hash does not depend on the order or numbering of
+// the GPUs, so nodes of the same system have the same fingerprint.
+//
+// The hash is computed using Weisfeiler-Lehman refinement: each GPU starts
+// with a label derived from its model, which is then repeatedly combined with
+// the labels of its peers and the links to them. Topologies that are not
+// isomorphic may in rare cases produce the same fingerprint.
+func (d DeviceList) Fingerprint() string {
+	labels := make([]string, len(d))
+	for i, gpu := range d {
+		labels[i] = hashLabel(gpu.Model)
+	}
+
+	for round := 0; round < len(d); round++ {
+		next := make([]string, len(d))
+		for i, gpu := range d {
+			var neighbours []string
+			for j, peer := range d {
+				if i == j {
+					continue
+				}
+				neighbours = append(neighbours, linkLabel(gpu.Links[peer.Index])+":"+labels[j])
+			}
+			sort.Strings(neighbours)
+			next[i] = hashLabel(labels[i] + "|" + strings.Join(neighbours, ","))
+		}
+		labels = next
+	}
+
+	sort.Strings(labels)
+	return hashLabel(fmt.Sprintf("%d|%s", len(d), strings.Join(labels, ",")))
+}
+
+func hashLabel(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
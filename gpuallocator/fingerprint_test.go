@@ -0,0 +1,146 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// withModel sets the model of all GPUs of the node.
+func (n TestNode) withModel(model string) TestNode {
+	for _, gpu := range n {
+		gpu.Model = model
+	}
+	return n
+}
+
+// permute returns a copy of the node where GPU 'i' is renumbered to
+// 'perm[i]' and the GPUs are listed in the new order.
+func (n TestNode) permute(perm []int) TestNode {
+	permuted := make(TestNode, len(n))
+	for i, gpu := range n {
+		permuted[perm[i]] = NewTestGPU(perm[i])
+		permuted[perm[i]].Model = gpu.Model
+	}
+	for i, gpu := range n {
+		for peer, links := range gpu.Links {
+			for _, link := range links {
+				permuted.AddLink(perm[i], perm[peer], link.Type)
+			}
+		}
+	}
+	return permuted
+}
+
+func TestFingerprint(t *testing.T) {
+	dgx1 := NewDGX1VoltaNode().withModel("Tesla V100-SXM2-32GB")
+	fingerprint := DeviceList(dgx1.Devices()).Fingerprint()
+
+	permuted := dgx1.permute([]int{5, 2, 7, 0, 3, 6, 1, 4})
+	require.Equal(t, fingerprint, DeviceList(permuted.Devices()).Fingerprint())
+
+	otherModel := NewDGX1VoltaNode().withModel("Tesla V100-SXM2-16GB")
+	require.NotEqual(t, fingerprint, DeviceList(otherModel.Devices()).Fingerprint())
+
+	degraded := NewDGX1VoltaNode().withModel("Tesla V100-SXM2-32GB")
+	delete(degraded[0].Links, 3)
+	delete(degraded[3].Links, 0)
+	require.NotEqual(t, fingerprint, DeviceList(degraded.Devices()).Fingerprint())
+
+	require.NotEqual(t, fingerprint, DeviceList(dgx1.Devices()[:4]).Fingerprint())
+}
+
+func TestIdentifySystem(t *testing.T) {
+	testCases := []struct {
+		description string
+		devices     DeviceList
+		expected    string
+	}{
+		{
+			"DGX-1 Volta",
+			NewDGX1VoltaNode().withModel("Tesla V100-SXM2-32GB").Devices(),
+			"DGX-1 (Volta)",
+		},
+		{
+			"DGX-1 Pascal",
+			NewDGX1PascalNode().withModel("Tesla P100-SXM2-16GB").Devices(),
+			"DGX-1 (Pascal)",
+		},
+		{
+			"renumbered DGX-1 Volta",
+			NewDGX1VoltaNode().withModel("Tesla V100-SXM2-16GB").permute([]int{7, 6, 5, 4, 3, 2, 1, 0}).Devices(),
+			"DGX-1 (Volta)",
+		},
+		{
+			"unknown model",
+			NewDGX1VoltaNode().withModel("Tesla V100-PCIE-32GB").Devices(),
+			"",
+		},
+		{
+			"unknown topology",
+			New4xRTX8000Node().withModel("Quadro RTX 8000").Devices(),
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			system, found := IdentifySystem(tc.devices)
+			if tc.expected == "" {
+				require.False(t, found)
+				return
+			}
+			require.True(t, found)
+			require.Equal(t, tc.expected, system.Name)
+		})
+	}
+}
+
+func TestIdentifySystemFromTopoMatrix(t *testing.T) {
+	system, found := LookupSystem("DGX A100")
+	require.True(t, found)
+
+	devices, err := ParseTopoMatrix(strings.NewReader(system.Topology("").TopoMatrix()))
+	require.NoError(t, err)
+	for _, d := range devices {
+		d.Model = "NVIDIA A100-SXM4-80GB"
+	}
+
+	identified, found := IdentifySystem(devices)
+	require.True(t, found)
+	require.Equal(t, system, identified)
+
+	delete(devices[2].Links, 5)
+	delete(devices[5].Links, 2)
+	require.False(t, system.Matches(devices))
+}
+
+func TestKnownSystemFingerprintsAreUnique(t *testing.T) {
+	seen := make(map[string]string)
+	for _, system := range KnownSystems() {
+		for _, fingerprint := range system.Fingerprints() {
+			require.NotContains(t, seen, fingerprint, "%v and %v have the same fingerprint", system.Name, seen[fingerprint])
+			seen[fingerprint] = system.Name
+		}
+	}
+
+	_, found := LookupSystem("DGX-3000")
+	require.False(t, found)
+}
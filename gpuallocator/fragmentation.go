@@ -0,0 +1,209 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import "github.com/NVIDIA/go-gpuallocator/internal/links"
+
+// maxExactScoreSize is the largest number of free GPUs for which the best
+// achievable set scores are computed exactly. The exact computation holds a
+// score for every subset of the free GPUs.
+const maxExactScoreSize = 16
+
+// LargestAllocatableSize returns the largest number of GPUs that the policy
+// of the allocator can currently allocate in a single request such that every
+// pair of the allocated GPUs is well connected.
+func (a *Allocator) LargestAllocatableSize() int {
+	available := a.available()
+	for size := len(available); size > 0; size-- {
+		allocated := a.policy.Allocate(available, nil, size)
+		if len(allocated) == size && wellConnected(allocated) {
+			return size
+		}
+	}
+	return 0
+}
+
+// wellConnected returns true if every pair of the GPUs is closely linked.
+func wellConnected(gpus []*Device) bool {
+	for i := range gpus {
+		for j := i + 1; j < len(gpus); j++ {
+			if !closelyLinked(gpus[i].Links[gpus[j].Index]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// BestAchievableScores returns the best BestEffort score of any set of free
+// GPUs for each set size. The score for a set of 'size' GPUs is held at index
+// 'size', so the slice has one more entry than there are free GPUs. Scores
+// are exact for up to 16 free GPUs and greedy approximations beyond that.
+func (a *Allocator) BestAchievableScores() []int {
+	return bestSetScores(a.available())
+}
+
+// bestSetScores returns the best BestEffort score of any set of the GPUs for
+// each set size, indexed by size.
+func bestSetScores(gpus []*Device) []int {
+	scores := pairScoreMatrix(gpus, calculateGPUPairScore)
+	if len(gpus) > maxExactScoreSize {
+		return greedyBestScores(scores)
+	}
+	return exactBestScores(scores)
+}
+
+// exactBestScores returns the best set score for each size from the pair
+// scores of the GPUs. It visits every subset of the GPUs.
+func exactBestScores(scores [][]int) []int {
+	// setScores[mask] holds the score of the set of GPUs in 'mask'. It is
+	// built from the set without its lowest GPU 'v' by adding the scores of
+	// the pairs that 'v' forms with the rest of the set.
+	n := len(scores)
+	best := make([]int, n+1)
+	setScores := make([]int, 1<<uint(n))
+	for mask := 1; mask < len(setScores); mask++ {
+		v := 0
+		for mask&(1<<uint(v)) == 0 {
+			v++
+		}
+		rest := mask &^ (1 << uint(v))
+		score := setScores[rest]
+		size := 1
+		for u := v + 1; u < n; u++ {
+			if rest&(1<<uint(u)) != 0 {
+				score += scores[v][u]
+				size++
+			}
+		}
+		setScores[mask] = score
+		if score > best[size] {
+			best[size] = score
+		}
+	}
+	return best
+}
+
+// greedyBestScores approximates the best set score for each size by growing a
+// set from the best pair, adding the GPU that adds the most to its score.
+func greedyBestScores(scores [][]int) []int {
+	n := len(scores)
+	best := make([]int, n+1)
+	if n < 2 {
+		return best
+	}
+
+	first, second := 0, 1
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if scores[i][j] > scores[first][second] {
+				first, second = i, j
+			}
+		}
+	}
+
+	set := []int{first, second}
+	inSet := map[int]bool{first: true, second: true}
+	best[2] = scores[first][second]
+	for size := 3; size <= n; size++ {
+		next, gain := -1, 0
+		for u := 0; u < n; u++ {
+			if inSet[u] {
+				continue
+			}
+			g := 0
+			for _, v := range set {
+				g += scores[u][v]
+			}
+			if next == -1 || g > gain {
+				next, gain = u, g
+			}
+		}
+		set = append(set, next)
+		inSet[next] = true
+		best[size] = best[size-1] + gain
+	}
+	return best
+}
+
+// StrandedGPUs returns the free GPUs that cannot form a well connected set
+// with any other free GPU. A GPU is stranded if no other free GPU is reachable
+// through an NVLink or a PCIe path that stays within its CPU.
+func (a *Allocator) StrandedGPUs() []*Device {
+	var stranded []*Device
+	for _, island := range islands(a.available()) {
+		if len(island) == 1 {
+			stranded = append(stranded, island[0])
+		}
+	}
+	return stranded
+}
+
+// FragmentationIndex returns the fraction of the free GPUs that lie outside
+// the largest group of well connected free GPUs. It is 0 if all free GPUs
+// can be allocated together without crossing the CPU, and approaches 1 as
+// the free GPUs are scattered across the node.
+func (a *Allocator) FragmentationIndex() float64 {
+	available := a.available()
+	if len(available) == 0 {
+		return 0
+	}
+
+	largest := 0
+	for _, island := range islands(available) {
+		if len(island) > largest {
+			largest = len(island)
+		}
+	}
+	return 1 - float64(largest)/float64(len(available))
+}
+
+// islands groups GPUs that are connected, directly or through other GPUs in
+// the group, by NVLinks or PCIe paths that do not cross the CPU.
+func islands(gpus []*Device) [][]*Device {
+	var groups [][]*Device
+	visited := make(map[*Device]bool)
+	for _, gpu := range gpus {
+		if visited[gpu] {
+			continue
+		}
+		visited[gpu] = true
+		group := []*Device{gpu}
+		for i := 0; i < len(group); i++ {
+			for _, peer := range gpus {
+				if !visited[peer] && closelyLinked(group[i].Links[peer.Index]) {
+					visited[peer] = true
+					group = append(group, peer)
+				}
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// closelyLinked returns true if the links include an NVLink or a PCIe path
+// that does not cross the CPU. Paths through the host bridges of a single CPU
+// (NODE) count as close.
+func closelyLinked(p2pLinks []P2PLink) bool {
+	for _, link := range p2pLinks {
+		if link.Type >= links.P2PLinkSameCPU {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newAllocatorWithFree returns an allocator for the devices in which only the
+// GPUs with the given indices are free.
+func newAllocatorWithFree(devices []*Device, policy Policy, free []int) *Allocator {
+	allocator := newAllocatorFrom(devices, policy)
+	freeSet := NewDeviceSet(GetDevicesFromIndices(devices, free)...)
+	for _, gpu := range devices {
+		if !freeSet.Contains(gpu) {
+			if err := allocator.AllocateSpecific(gpu); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return allocator
+}
+
+func TestFragmentationIndex(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	testCases := []struct {
+		description   string
+		free          []int
+		fragmentation float64
+		stranded      []int
+	}{
+		{"no free GPUs", nil, 0, nil},
+		{"single GPU", []int{0}, 0, []int{0}},
+		{"same socket", []int{0, 1, 2, 3}, 0, nil},
+		{"NVLinked across sockets", []int{0, 4}, 0, nil},
+		{"split across sockets", []int{0, 1, 6, 7}, 0.5, nil},
+		{"isolated GPU", []int{0, 1, 2, 7}, 0.25, []int{7}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			allocator := newAllocatorWithFree(devices, NewBestEffortPolicy(), tc.free)
+			require.InDelta(t, tc.fragmentation, allocator.FragmentationIndex(), 1e-9)
+			require.ElementsMatch(t, GetDevicesFromIndices(devices, tc.stranded), allocator.StrandedGPUs())
+		})
+	}
+}
+
+func TestFragmentationIndexSameCPU(t *testing.T) {
+	devices := New4xRTX8000Node().Devices()
+
+	// GPUs 0 and 1 are only linked through the PCIe host bridges of one CPU.
+	allocator := newAllocatorWithFree(devices, NewBestEffortPolicy(), []int{0, 1})
+	require.Equal(t, 0.0, allocator.FragmentationIndex())
+	require.Empty(t, allocator.StrandedGPUs())
+
+	allocator = newAllocatorWithFree(devices, NewBestEffortPolicy(), []int{0, 2})
+	require.Equal(t, 0.5, allocator.FragmentationIndex())
+	require.Len(t, allocator.StrandedGPUs(), 2)
+}
+
+func TestLargestAllocatableSize(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+
+	// Five GPUs are free, but only GPUs 0, 1 and 2 are all linked to each
+	// other without crossing the CPU.
+	allocator := newAllocatorWithFree(devices, NewBestEffortPolicy(), []int{0, 1, 2, 5, 6})
+	require.Equal(t, 3, allocator.LargestAllocatableSize())
+
+	allocator = newAllocatorWithFree(devices, NewBestEffortPolicy(), []int{0, 1, 2, 3})
+	require.Equal(t, 4, allocator.LargestAllocatableSize())
+
+	// The static DGX policy only allocates 1, 2, 4 or 8 GPUs from fixed sets.
+	allocator = newAllocatorWithFree(devices, NewStaticDGX1Policy(GPUTypeVolta), []int{0, 1, 2, 5, 6})
+	require.Equal(t, 2, allocator.LargestAllocatableSize())
+
+	allocator = newAllocatorWithFree(devices, NewBestEffortPolicy(), nil)
+	require.Equal(t, 0, allocator.LargestAllocatableSize())
+}
+
+func TestBestAchievableScores(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorWithFree(devices, NewBestEffortPolicy(), []int{0, 1, 2, 3, 4})
+
+	free := allocator.available()
+	expected := make([]int, len(free)+1)
+	for size := 1; size <= len(free); size++ {
+		iterateGPUSets(free, size, func(set []*Device) {
+			if score := calculateGPUSetScore(set); score > expected[size] {
+				expected[size] = score
+			}
+		})
+	}
+
+	scores := allocator.BestAchievableScores()
+	require.Equal(t, expected, scores)
+	require.Equal(t, 0, scores[1])
+	require.Equal(t, calculateGPUSetScore(GetDevicesFromIndices(devices, []int{0, 1, 2, 3})), scores[4])
+}
+
+func TestGreedyBestScores(t *testing.T) {
+	devices := newTestDevices(4)
+	scores := greedyBestScores(pairScoreMatrix(devices, square.pairScore))
+	require.Equal(t, []int{0, 0, 10, 21, 42}, scores)
+	require.Equal(t, []int{0, 0}, greedyBestScores(pairScoreMatrix(devices[:1], square.pairScore)))
+}
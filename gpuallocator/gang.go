@@ -0,0 +1,280 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Request describes a group of GPUs requested on behalf of an owner.
+type Request struct {
+	Owner string
+	// Group is the owner group of the request, such as the replicas of an
+	// inference service. Peer-aware policies use it to place the request
+	// relative to the other allocations of the group.
+	Group string
+	// Priority orders requests for preemption. Allocations may only be
+	// preempted by requests of a higher priority. It defaults to
+	// DefaultPriority.
+	Priority int
+	Size     int
+	// Required holds the GPUs that must be part of the group.
+	Required []*Device
+}
+
+// GangPolicy is implemented by policies that can allocate several groups of
+// GPUs together.
+type GangPolicy interface {
+	Policy
+	// AllocateGroups allocates a group of sizes[i] GPUs that contains all of
+	// required[i] for each i. The groups are chosen together to maximize
+	// their total score and are returned in the order of 'sizes'. If the
+	// groups cannot be allocated, it returns nil.
+	AllocateGroups(available []*Device, required [][]*Device, sizes []int) [][]*Device
+}
+
+// AllocateGang allocates a group of GPUs for each of the requests. The groups
+// are chosen together by the policy of the allocator to maximize the sum of
+// their scores, rather than one after the other. Either all groups are
+// allocated or, if any of the requests cannot be satisfied, none are and an
+// error is returned. The groups are returned in the order of the requests.
+// An error is also returned if the policy is not a GangPolicy.
+func (a *Allocator) AllocateGang(requests []Request) ([][]*Device, error) {
+	groups, err := a.planGang(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, request := range requests {
+		if err := a.AllocateSpecificFor(request.Owner, groups[i]...); err != nil {
+			for _, group := range groups[:i] {
+				a.Free(group...)
+			}
+			return nil, fmt.Errorf("internal error while allocating GPUs: %v", err)
+		}
+		a.recordRequest(request, groups[i]...)
+	}
+
+	return groups, nil
+}
+
+// planGang validates the requests and asks the policy of the allocator for
+// their groups.
+func (a *Allocator) planGang(requests []Request) ([][]*Device, error) {
+	policy, ok := a.policy.(GangPolicy)
+	if !ok {
+		return nil, fmt.Errorf("policy does not support gang allocation")
+	}
+
+	available := a.available()
+	availableSet := NewDeviceSet(available...)
+
+	// Resolve the required GPUs and check that no GPU is required by two
+	// requests.
+	total := 0
+	sizes := make([]int, len(requests))
+	required := make([][]*Device, len(requests))
+	reserved := make(map[*Device]int)
+	for i, request := range requests {
+		if request.Size <= 0 {
+			return nil, fmt.Errorf("invalid size %d for request %d", request.Size, i)
+		}
+		if len(request.Required) > request.Size {
+			return nil, fmt.Errorf("request %d requires more GPUs than its size", i)
+		}
+		for _, gpu := range request.Required {
+			current, ok := availableSet[gpu.UUID]
+			if !ok {
+				return nil, fmt.Errorf("device '%v' required by request %d is unavailable for allocation", gpu, i)
+			}
+			if other, ok := reserved[current]; ok && other != i {
+				return nil, fmt.Errorf("device '%v' is required by requests %d and %d", gpu, other, i)
+			}
+			reserved[current] = i
+			required[i] = append(required[i], current)
+		}
+		sizes[i] = request.Size
+		total += request.Size
+	}
+	if total > len(available) {
+		return nil, fmt.Errorf("requests for %d GPUs exceed the %d available", total, len(available))
+	}
+
+	groups := policy.AllocateGroups(available, required, sizes)
+	if groups == nil {
+		return nil, fmt.Errorf("unable to allocate all %d requests", len(requests))
+	}
+	return groups, nil
+}
+
+// AllocateGroups finds the groups of GPUs with the highest partition score,
+// i.e. the highest sum of set scores under the pair score function and set
+// objective of the policy. Unlike Allocate, the GPUs left over by the groups
+// do not contribute to the score.
+//
+// The groups are searched exhaustively with branch and bound: an assignment
+// is abandoned as soon as the best scores that the remaining groups could
+// reach cannot beat the best assignment found so far.
+func (p *bestEffortPolicy) AllocateGroups(available []*Device, required [][]*Device, sizes []int) [][]*Device {
+	total := 0
+	for _, size := range sizes {
+		if size <= 0 {
+			return nil
+		}
+		total += size
+	}
+	if total > len(available) {
+		return nil
+	}
+
+	reserved := make(map[*Device]int)
+	for i := range required {
+		for _, gpu := range required[i] {
+			reserved[gpu] = i
+		}
+	}
+
+	// Place the largest groups first, and groups with required GPUs before
+	// the groups of the same size without them.
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if sizes[order[i]] != sizes[order[j]] {
+			return sizes[order[i]] > sizes[order[j]]
+		}
+		return len(required[order[i]]) > len(required[order[j]])
+	})
+
+	// Groups of the same size without required GPUs are interchangeable.
+	// They are only considered in increasing order of their first GPU, so
+	// that each assignment is searched once rather than once per permutation
+	// of the groups, as in iterateGPUPartitions.
+	interchangeable := make([]bool, len(order))
+	for k := 1; k < len(order); k++ {
+		prev, next := order[k-1], order[k]
+		interchangeable[k] = sizes[prev] == sizes[next] && len(required[prev]) == 0 && len(required[next]) == 0
+	}
+
+	// Score the groups with the pair scores of the policy, computed once.
+	position := make(map[*Device]int)
+	for i, gpu := range available {
+		position[gpu] = i
+	}
+	scores := pairScoreMatrix(available, p.pairScore)
+	scored := &bestEffortPolicy{
+		pairScore: func(gpu0 *Device, gpu1 *Device) int {
+			return scores[position[gpu0]][position[gpu1]]
+		},
+		objective: p.objective,
+	}
+
+	// bound[k] is an upper bound on the score of the groups from order[k]
+	// onwards.
+	bounds := p.setScoreBounds(scores)
+	bound := make([]int, len(order)+1)
+	for k := len(order) - 1; k >= 0; k-- {
+		bound[k] = bound[k+1] + bounds[sizes[order[k]]]
+	}
+
+	used := make(map[*Device]bool)
+	current := make([][]*Device, len(sizes))
+	var best [][]*Device
+	bestScore := 0
+
+	var search func(k int, score int)
+	search = func(k int, score int) {
+		if k == len(order) {
+			if best == nil || score > bestScore {
+				best = make([][]*Device, len(current))
+				copy(best, current)
+				bestScore = score
+			}
+			return
+		}
+
+		g := order[k]
+		first := 0
+		if interchangeable[k] {
+			first = position[current[order[k-1]][0]] + 1
+		}
+		var candidates []*Device
+		for _, gpu := range available[first:] {
+			owner, isReserved := reserved[gpu]
+			if used[gpu] || (isReserved && owner != g) {
+				continue
+			}
+			candidates = append(candidates, gpu)
+		}
+
+		iterateGPUSets(candidates, sizes[g], func(set []*Device) {
+			if !gpuSetContainsAll(set, required[g]) {
+				return
+			}
+			next := score + scored.setScore(set)
+			if best != nil && next+bound[k+1] <= bestScore {
+				return
+			}
+			current[g] = append([]*Device{}, set...)
+			for _, gpu := range current[g] {
+				used[gpu] = true
+			}
+			search(k+1, next)
+			for _, gpu := range current[g] {
+				used[gpu] = false
+			}
+		})
+	}
+	search(0, 0)
+
+	return best
+}
+
+// setScoreBounds returns an upper bound on the set score of the policy for
+// any set of the GPUs, indexed by set size, given their pair score matrix.
+// For the sum of the pair scores, the bounds are the exact best scores for up
+// to maxExactScoreSize GPUs, and the sum of the highest pair scores beyond
+// that. The other objectives never exceed the highest pair score.
+func (p *bestEffortPolicy) setScoreBounds(scores [][]int) []int {
+	if p.objective == SetObjectiveSum && len(scores) <= maxExactScoreSize {
+		return exactBestScores(scores)
+	}
+
+	var pairs []int
+	for i := range scores {
+		for j := i + 1; j < len(scores); j++ {
+			pairs = append(pairs, scores[i][j])
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(pairs)))
+
+	bounds := make([]int, len(scores)+1)
+	sum, taken := 0, 0
+	for size := 2; size <= len(scores); size++ {
+		if p.objective != SetObjectiveSum {
+			bounds[size] = pairs[0]
+			continue
+		}
+		for ; taken < size*(size-1)/2; taken++ {
+			sum += pairs[taken]
+		}
+		bounds[size] = sum
+	}
+	return bounds
+}
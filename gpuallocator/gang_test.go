@@ -0,0 +1,288 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// newGangNode returns a node with an NVLinked quad of GPUs 0-3, in which GPUs
+// 2 and 3 have additional NVLinks, and an NVLinked pair of GPUs 4 and 5.
+func newGangNode() TestNode {
+	var node TestNode
+	for i := 0; i < 6; i++ {
+		node = append(node, NewTestGPU(i))
+	}
+	link := func(i, j int, linkType links.P2PLinkType) {
+		node.AddLink(i, j, linkType)
+		node.AddLink(j, i, linkType)
+	}
+	link(0, 1, links.SingleNVLINKLink)
+	link(0, 2, links.SingleNVLINKLink)
+	link(0, 3, links.SingleNVLINKLink)
+	link(1, 2, links.SingleNVLINKLink)
+	link(1, 3, links.SingleNVLINKLink)
+	link(2, 3, links.FourNVLINKLinks)
+	link(4, 5, links.SingleNVLINKLink)
+	return node
+}
+
+func TestAllocateGang(t *testing.T) {
+	devices := newGangNode().Devices()
+	requests := []Request{
+		{Owner: "small", Size: 2},
+		{Owner: "large", Size: 4},
+	}
+
+	// Allocating the requests one after the other gives the small request
+	// the best pair and splits the large request across both NVLink groups.
+	sequential := newAllocatorFrom(devices, NewBestEffortPolicy())
+	require.ElementsMatch(t, []int{2, 3}, indicesOf(sequential.AllocateFor("small", 2)))
+	require.ElementsMatch(t, []int{0, 1, 4, 5}, indicesOf(sequential.AllocateFor("large", 4)))
+
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+	groups, err := allocator.AllocateGang(requests)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	require.Equal(t, []int{4, 5}, indicesOf(groups[0]))
+	require.Equal(t, []int{0, 1, 2, 3}, indicesOf(groups[1]))
+
+	for i, request := range requests {
+		for _, gpu := range groups[i] {
+			owner, allocated := allocator.Owner(gpu)
+			require.True(t, allocated)
+			require.Equal(t, request.Owner, owner)
+		}
+	}
+	require.Empty(t, allocator.available())
+}
+
+func TestAllocateGangRequired(t *testing.T) {
+	devices := newGangNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+
+	groups, err := allocator.AllocateGang([]Request{
+		{Owner: "a", Size: 2, Required: []*Device{devices[2]}},
+		{Owner: "b", Size: 2, Required: []*Device{devices[3]}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, indicesOf(groups[0]), 2)
+	require.Contains(t, indicesOf(groups[1]), 3)
+	require.NotContains(t, indicesOf(groups[0]), 3)
+}
+
+func TestAllocateGangAllOrNone(t *testing.T) {
+	devices := newGangNode().Devices()
+
+	testCases := []struct {
+		description string
+		allocated   []int
+		requests    []Request
+	}{
+		{
+			"too many GPUs",
+			[]int{0},
+			[]Request{{Owner: "a", Size: 4}, {Owner: "b", Size: 2}},
+		},
+		{
+			"required GPU unavailable",
+			[]int{0},
+			[]Request{{Owner: "a", Size: 1}, {Owner: "b", Size: 2, Required: []*Device{devices[0]}}},
+		},
+		{
+			"required by two requests",
+			nil,
+			[]Request{{Owner: "a", Size: 1, Required: []*Device{devices[0]}}, {Owner: "b", Size: 1, Required: []*Device{devices[0]}}},
+		},
+		{
+			"more required than requested",
+			nil,
+			[]Request{{Owner: "a", Size: 1, Required: []*Device{devices[0], devices[1]}}},
+		},
+		{
+			"invalid size",
+			nil,
+			[]Request{{Owner: "a", Size: 2}, {Owner: "b", Size: 0}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+			require.NoError(t, allocator.AllocateSpecific(GetDevicesFromIndices(devices, tc.allocated)...))
+			before := indicesOf(allocator.available())
+
+			groups, err := allocator.AllocateGang(tc.requests)
+			require.Error(t, err)
+			require.Nil(t, groups)
+			require.Equal(t, before, indicesOf(allocator.available()))
+		})
+	}
+}
+
+func TestAllocateGangSetObjective(t *testing.T) {
+	devices := newGangNode().Devices()
+	requests := []Request{
+		{Owner: "small", Size: 2},
+		{Owner: "large", Size: 4},
+	}
+
+	// By its lowest pair, the best pair makes up for a large group without
+	// NVLinks between all of its GPUs.
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy(WithSetObjective(SetObjectiveMinPair)))
+	groups, err := allocator.AllocateGang(requests)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3}, indicesOf(groups[0]))
+	require.Equal(t, []int{0, 1, 4, 5}, indicesOf(groups[1]))
+}
+
+func TestAllocateGangMatchesObjective(t *testing.T) {
+	devices := newGangNode().Devices()
+	sizes := []int{3, 2, 1}
+	byIndex := func(gpu0 *Device, gpu1 *Device) int {
+		return gpu0.Index * gpu1.Index
+	}
+
+	policies := map[string]*bestEffortPolicy{
+		"sum":      NewBestEffortPolicy().(*bestEffortPolicy),
+		"min pair": NewBestEffortPolicy(WithSetObjective(SetObjectiveMinPair)).(*bestEffortPolicy),
+		"ring":     NewBestEffortPolicy(WithSetObjective(SetObjectiveRing)).(*bestEffortPolicy),
+		"tree":     NewBestEffortPolicy(WithSetObjective(SetObjectiveTree)).(*bestEffortPolicy),
+		"pair":     NewBestEffortPolicy(WithPairScore(byIndex)).(*bestEffortPolicy),
+	}
+
+	for name, policy := range policies {
+		t.Run(name, func(t *testing.T) {
+			// Find the best partition score of any assignment by brute force.
+			best := 0
+			var assign func(k int, free []*Device, groups [][]*Device)
+			assign = func(k int, free []*Device, groups [][]*Device) {
+				if k == len(sizes) {
+					if score := policy.partitionScore(groups); score > best {
+						best = score
+					}
+					return
+				}
+				iterateGPUSets(free, sizes[k], func(set []*Device) {
+					var rest []*Device
+					for _, gpu := range free {
+						if !gpuSetContains(set, gpu) {
+							rest = append(rest, gpu)
+						}
+					}
+					assign(k+1, rest, append(groups, append([]*Device{}, set...)))
+				})
+			}
+			assign(0, devices, nil)
+
+			groups := policy.AllocateGroups(devices, make([][]*Device, len(sizes)), sizes)
+			require.Len(t, groups, len(sizes))
+			for i, size := range sizes {
+				require.Len(t, groups[i], size)
+			}
+			require.Equal(t, best, policy.partitionScore(groups))
+		})
+	}
+}
+
+func TestAllocateGangUnsupportedPolicy(t *testing.T) {
+	allocator := newAllocatorFrom(newGangNode().Devices(), NewSimplePolicy())
+	groups, err := allocator.AllocateGang([]Request{{Owner: "a", Size: 2}})
+	require.Error(t, err)
+	require.Nil(t, groups)
+	require.Len(t, allocator.available(), 6)
+}
+
+// newNVSwitchNode returns a node of 16 GPUs with six NVLinks between every
+// pair, in which GPUs 0-7 and GPUs 8-15 are attached to different CPUs.
+func newNVSwitchNode() TestNode {
+	var node TestNode
+	for i := 0; i < 16; i++ {
+		node = append(node, NewTestGPU(i))
+	}
+	for i := range node {
+		for j := range node {
+			if i == j {
+				continue
+			}
+			pcie := links.P2PLinkSameCPU
+			if i/8 != j/8 {
+				pcie = links.P2PLinkCrossCPU
+			}
+			node.AddLink(i, j, pcie)
+			node.AddLink(i, j, links.SixNVLINKLinks)
+		}
+	}
+	return node
+}
+
+func TestAllocateGangLargeNode(t *testing.T) {
+	testCases := []struct {
+		description string
+		sizes       []int
+		crossing    int
+	}{
+		{"five pairs", []int{2, 2, 2, 2, 2}, 0},
+		{"eight pairs", []int{2, 2, 2, 2, 2, 2, 2, 2}, 0},
+		{"five triples", []int{3, 3, 3, 3, 3}, 1},
+		{"mixed sizes", []int{4, 3, 3, 2, 2, 1}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var requests []Request
+			for i, size := range tc.sizes {
+				requests = append(requests, Request{Owner: fmt.Sprintf("job-%d", i), Size: size})
+			}
+
+			allocator := newAllocatorFrom(newNVSwitchNode().Devices(), NewBestEffortPolicy())
+			done := make(chan struct{})
+			var groups [][]*Device
+			var err error
+			go func() {
+				defer close(done)
+				groups, err = allocator.AllocateGang(requests)
+			}()
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("AllocateGang did not finish within 10s")
+			}
+			require.NoError(t, err)
+
+			// Only the groups that cannot fit on one CPU span both.
+			crossing := 0
+			for i, group := range groups {
+				require.Len(t, group, tc.sizes[i])
+				cpus := make(map[int]bool)
+				for _, gpu := range group {
+					cpus[gpu.Index/8] = true
+				}
+				if len(cpus) > 1 {
+					crossing++
+				}
+			}
+			require.Equal(t, tc.crossing, crossing)
+		})
+	}
+}
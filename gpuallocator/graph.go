@@ -0,0 +1,232 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxEdgeWidth is the width of the edge with the highest pair score.
+	maxEdgeWidth = 8.0
+	// minEdgeWidth is the minimum width of an edge, so that edges with a low
+	// pair score remain visible.
+	minEdgeWidth = 0.5
+	// highlightColor is the color of highlighted GPUs and links.
+	highlightColor = "#76b900"
+)
+
+// GraphOption defines a functional option for rendering topology graphs.
+type GraphOption func(*graphOptions)
+
+type graphOptions struct {
+	highlight DeviceSet
+}
+
+// WithHighlight highlights the specified GPUs, and the links between them,
+// in the rendered graph. This can be used to show an allocation.
+func WithHighlight(devices ...*Device) GraphOption {
+	return func(o *graphOptions) {
+		o.highlight.Insert(devices...)
+	}
+}
+
+// topologyGraph is the representation of a DeviceList shared by the graph
+// renderers.
+type topologyGraph struct {
+	// groups holds the GPUs grouped by NUMA node. GPUs without a known NUMA
+	// node are held in the group for node -1.
+	groups map[int][]*Device
+	edges  []topologyEdge
+	graphOptions
+}
+
+type topologyEdge struct {
+	gpu0  *Device
+	gpu1  *Device
+	label string
+	width float64
+}
+
+func newTopologyGraph(d DeviceList, opts ...GraphOption) *topologyGraph {
+	g := &topologyGraph{
+		groups: make(map[int][]*Device),
+		graphOptions: graphOptions{
+			highlight: NewDeviceSet(),
+		},
+	}
+	for _, opt := range opts {
+		opt(&g.graphOptions)
+	}
+
+	for _, gpu := range d {
+		node := -1
+		if gpu.CPUAffinity != nil {
+			node = int(*gpu.CPUAffinity)
+		}
+		g.groups[node] = append(g.groups[node], gpu)
+	}
+
+	maxScore := 0
+	for i, gpu0 := range d {
+		for _, gpu1 := range d[i+1:] {
+			links := gpu0.Links[gpu1.Index]
+			if len(links) == 0 {
+				continue
+			}
+			score := calculateGPUPairScore(gpu0, gpu1)
+			if score > maxScore {
+				maxScore = score
+			}
+			g.edges = append(g.edges, topologyEdge{
+				gpu0:  gpu0,
+				gpu1:  gpu1,
+				label: linkLabel(links),
+				width: float64(score),
+			})
+		}
+	}
+
+	for i := range g.edges {
+		if maxScore > 0 {
+			g.edges[i].width = g.edges[i].width * maxEdgeWidth / float64(maxScore)
+		}
+		if g.edges[i].width < minEdgeWidth {
+			g.edges[i].width = minEdgeWidth
+		}
+	}
+
+	return g
+}
+
+// nodes returns the NUMA nodes of the graph in ascending order.
+func (g *topologyGraph) nodes() []int {
+	var nodes []int
+	for node := range g.groups {
+		nodes = append(nodes, node)
+	}
+	sort.Ints(nodes)
+	return nodes
+}
+
+func (g *topologyGraph) isHighlighted(e topologyEdge) bool {
+	return g.highlight.Contains(e.gpu0) && g.highlight.Contains(e.gpu1)
+}
+
+// linkLabel returns the label for a set of links between two GPUs, using the
+// abbreviations of 'nvidia-smi topo -m'. As with nvidia-smi, NVLinks take
+// precedence over the PCIe link.
+func linkLabel(links []P2PLink) string {
+	label := "N/A"
+	for _, link := range links {
+		if link.Type.IsNVLink() {
+			return link.Type.Abbreviation()
+		}
+		label = link.Type.Abbreviation()
+	}
+	return label
+}
+
+func graphNodeID(gpu *Device) string {
+	return fmt.Sprintf("gpu%d", gpu.Index)
+}
+
+// DOT renders the topology of the devices as a Graphviz DOT graph. GPUs are
+// grouped by NUMA node and the links between them are labeled by link type,
+// with a width proportional to their BestEffort pair score.
+func (d DeviceList) DOT(opts ...GraphOption) string {
+	g := newTopologyGraph(d, opts...)
+
+	var b strings.Builder
+	b.WriteString("graph topology {\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, node := range g.nodes() {
+		indent := "  "
+		if node >= 0 {
+			fmt.Fprintf(&b, "  subgraph cluster_numa%d {\n", node)
+			fmt.Fprintf(&b, "    label=\"NUMA %d\";\n", node)
+			indent = "    "
+		}
+		for _, gpu := range g.groups[node] {
+			attrs := fmt.Sprintf("label=\"GPU %d\"", gpu.Index)
+			if g.highlight.Contains(gpu) {
+				attrs += fmt.Sprintf(", style=filled, fillcolor=\"%s\"", highlightColor)
+			}
+			fmt.Fprintf(&b, "%s%s [%s];\n", indent, graphNodeID(gpu), attrs)
+		}
+		if node >= 0 {
+			b.WriteString("  }\n")
+		}
+	}
+	for _, e := range g.edges {
+		attrs := fmt.Sprintf("label=\"%s\", penwidth=%.2f", e.label, e.width)
+		if g.isHighlighted(e) {
+			attrs += fmt.Sprintf(", color=\"%s\"", highlightColor)
+		}
+		fmt.Fprintf(&b, "  %s -- %s [%s];\n", graphNodeID(e.gpu0), graphNodeID(e.gpu1), attrs)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// Mermaid renders the topology of the devices as a Mermaid flowchart. GPUs
+// are grouped by NUMA node and the links between them are labeled by link
+// type, with a width proportional to their BestEffort pair score.
+func (d DeviceList) Mermaid(opts ...GraphOption) string {
+	g := newTopologyGraph(d, opts...)
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, node := range g.nodes() {
+		indent := "  "
+		if node >= 0 {
+			fmt.Fprintf(&b, "  subgraph numa%d [\"NUMA %d\"]\n", node, node)
+			indent = "    "
+		}
+		for _, gpu := range g.groups[node] {
+			fmt.Fprintf(&b, "%s%s[\"GPU %d\"]\n", indent, graphNodeID(gpu), gpu.Index)
+		}
+		if node >= 0 {
+			b.WriteString("  end\n")
+		}
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "  %s ---|\"%s\"| %s\n", graphNodeID(e.gpu0), e.label, graphNodeID(e.gpu1))
+	}
+	for i, e := range g.edges {
+		style := fmt.Sprintf("stroke-width:%.2fpx", e.width)
+		if g.isHighlighted(e) {
+			style += ",stroke:" + highlightColor
+		}
+		fmt.Fprintf(&b, "  linkStyle %d %s\n", i, style)
+	}
+	var ids []string
+	for _, gpu := range d {
+		if g.highlight.Contains(gpu) {
+			ids = append(ids, graphNodeID(gpu))
+		}
+	}
+	if len(ids) != 0 {
+		fmt.Fprintf(&b, "  classDef highlight fill:%s\n", highlightColor)
+		fmt.Fprintf(&b, "  class %s highlight\n", strings.Join(ids, ","))
+	}
+
+	return b.String()
+}
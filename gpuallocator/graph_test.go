@@ -0,0 +1,143 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// newTwoSocketNode creates a node with an NVLinked pair of GPUs on NUMA node
+// 0 and a single GPU on NUMA node 1.
+func newTwoSocketNode() DeviceList {
+	node := TestNode{
+		NewTestGPU(0),
+		NewTestGPU(1),
+		NewTestGPU(2),
+	}.setNUMANodes(0, 0, 1)
+	node.addBidirectionalLink(0, 1, links.P2PLinkSameCPU)
+	node.addBidirectionalLink(0, 1, links.TwoNVLINKLinks)
+	node.addBidirectionalLink(0, 2, links.P2PLinkCrossCPU)
+	node.addBidirectionalLink(1, 2, links.P2PLinkCrossCPU)
+	return node.Devices()
+}
+
+func TestDOT(t *testing.T) {
+	devices := newTwoSocketNode()
+
+	expected := `graph topology {
+  node [shape=box];
+  subgraph cluster_numa0 {
+    label="NUMA 0";
+    gpu0 [label="GPU 0"];
+    gpu1 [label="GPU 1"];
+  }
+  subgraph cluster_numa1 {
+    label="NUMA 1";
+    gpu2 [label="GPU 2"];
+  }
+  gpu0 -- gpu1 [label="NV2", penwidth=8.00];
+  gpu0 -- gpu2 [label="SYS", penwidth=0.50];
+  gpu1 -- gpu2 [label="SYS", penwidth=0.50];
+}
+`
+	require.Equal(t, expected, devices.DOT())
+
+	highlighted := `graph topology {
+  node [shape=box];
+  subgraph cluster_numa0 {
+    label="NUMA 0";
+    gpu0 [label="GPU 0", style=filled, fillcolor="#76b900"];
+    gpu1 [label="GPU 1", style=filled, fillcolor="#76b900"];
+  }
+  subgraph cluster_numa1 {
+    label="NUMA 1";
+    gpu2 [label="GPU 2"];
+  }
+  gpu0 -- gpu1 [label="NV2", penwidth=8.00, color="#76b900"];
+  gpu0 -- gpu2 [label="SYS", penwidth=0.50];
+  gpu1 -- gpu2 [label="SYS", penwidth=0.50];
+}
+`
+	require.Equal(t, highlighted, devices.DOT(WithHighlight(devices[0], devices[1])))
+}
+
+func TestDOTWithoutNUMA(t *testing.T) {
+	devices := DeviceList(New4xRTX8000Node().Devices()[:2])
+
+	expected := `graph topology {
+  node [shape=box];
+  gpu0 [label="GPU 0"];
+  gpu1 [label="GPU 1"];
+  gpu0 -- gpu1 [label="NODE", penwidth=8.00];
+}
+`
+	require.Equal(t, expected, devices.DOT())
+}
+
+func TestMermaid(t *testing.T) {
+	devices := newTwoSocketNode()
+
+	expected := `graph LR
+  subgraph numa0 ["NUMA 0"]
+    gpu0["GPU 0"]
+    gpu1["GPU 1"]
+  end
+  subgraph numa1 ["NUMA 1"]
+    gpu2["GPU 2"]
+  end
+  gpu0 ---|"NV2"| gpu1
+  gpu0 ---|"SYS"| gpu2
+  gpu1 ---|"SYS"| gpu2
+  linkStyle 0 stroke-width:8.00px,stroke:#76b900
+  linkStyle 1 stroke-width:0.50px
+  linkStyle 2 stroke-width:0.50px
+  classDef highlight fill:#76b900
+  class gpu0,gpu1 highlight
+`
+	require.Equal(t, expected, devices.Mermaid(WithHighlight(devices[0], devices[1])))
+}
+
+func TestLinkLabel(t *testing.T) {
+	testCases := []struct {
+		links    []links.P2PLinkType
+		expected string
+	}{
+		{nil, "N/A"},
+		{[]links.P2PLinkType{links.P2PLinkCrossCPU}, "SYS"},
+		{[]links.P2PLinkType{links.P2PLinkSameCPU}, "NODE"},
+		{[]links.P2PLinkType{links.P2PLinkHostBridge}, "PHB"},
+		{[]links.P2PLinkType{links.P2PLinkMultiSwitch}, "PXB"},
+		{[]links.P2PLinkType{links.P2PLinkSingleSwitch}, "PIX"},
+		{[]links.P2PLinkType{links.P2PLinkSameBoard}, "PIX"},
+		{[]links.P2PLinkType{links.P2PLinkSameCPU, links.TwelveNVLINKLinks}, "NV12"},
+		{[]links.P2PLinkType{links.EighteenNVLINKLinks, links.P2PLinkCrossCPU}, "NV18"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			var p2pLinks []P2PLink
+			for _, linkType := range tc.links {
+				p2pLinks = append(p2pLinks, P2PLink{Type: linkType})
+			}
+			require.Equal(t, tc.expected, linkLabel(p2pLinks))
+		})
+	}
+}
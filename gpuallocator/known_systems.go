@@ -0,0 +1,194 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// KnownSystem describes the GPU topology of a known system.
+//
+// Systems in which the GPUs are connected through NVSwitches are described
+// with the NVLinks shown by 'nvidia-smi topo -m', which NewDevices() resolves
+// from the NVLinks of each GPU to the NVSwitch fabric.
+type KnownSystem struct {
+	// Name is the name of the system.
+	Name string
+	// Models holds the GPU models the system is available with.
+	Models []string
+
+	gpus    int
+	pcie    links.P2PLinkType
+	nvlinks map[[2]int]links.P2PLinkType
+}
+
+// nvlinkMesh returns the NVLinks of the hybrid cube-mesh topology of the
+// DGX-1, using the specified link type for links that are doubled on Volta.
+func nvlinkMesh(double links.P2PLinkType) map[[2]int]links.P2PLinkType {
+	return map[[2]int]links.P2PLinkType{
+		{0, 1}: links.SingleNVLINKLink,
+		{0, 2}: links.SingleNVLINKLink,
+		{0, 3}: double,
+		{0, 4}: double,
+		{1, 2}: double,
+		{1, 3}: links.SingleNVLINKLink,
+		{1, 5}: double,
+		{2, 3}: double,
+		{2, 6}: links.SingleNVLINKLink,
+		{3, 7}: links.SingleNVLINKLink,
+		{4, 5}: links.SingleNVLINKLink,
+		{4, 6}: links.SingleNVLINKLink,
+		{4, 7}: double,
+		{5, 6}: double,
+		{5, 7}: links.SingleNVLINKLink,
+		{6, 7}: double,
+	}
+}
+
+// nvlinkAllToAll returns NVLinks of the specified type between all pairs of
+// GPUs, as for systems with NVSwitches.
+func nvlinkAllToAll(gpus int, nvlink links.P2PLinkType) map[[2]int]links.P2PLinkType {
+	nvlinks := make(map[[2]int]links.P2PLinkType)
+	for i := 0; i < gpus; i++ {
+		for j := i + 1; j < gpus; j++ {
+			nvlinks[[2]int{i, j}] = nvlink
+		}
+	}
+	return nvlinks
+}
+
+// knownSystems is the catalog of known systems.
+var knownSystems = []*KnownSystem{
+	{
+		Name:    "DGX-1 (Pascal)",
+		Models:  []string{"Tesla P100-SXM2-16GB"},
+		gpus:    8,
+		pcie:    links.P2PLinkCrossCPU,
+		nvlinks: nvlinkMesh(links.SingleNVLINKLink),
+	},
+	{
+		Name:    "DGX-1 (Volta)",
+		Models:  []string{"Tesla V100-SXM2-16GB", "Tesla V100-SXM2-32GB"},
+		gpus:    8,
+		pcie:    links.P2PLinkCrossCPU,
+		nvlinks: nvlinkMesh(links.TwoNVLINKLinks),
+	},
+	{
+		Name:    "DGX-2",
+		Models:  []string{"Tesla V100-SXM3-32GB"},
+		gpus:    16,
+		nvlinks: nvlinkAllToAll(16, links.SixNVLINKLinks),
+	},
+	{
+		Name:    "DGX A100",
+		Models:  []string{"A100-SXM4-40GB", "NVIDIA A100-SXM4-40GB", "NVIDIA A100-SXM4-80GB"},
+		gpus:    8,
+		nvlinks: nvlinkAllToAll(8, links.TwelveNVLINKLinks),
+	},
+	{
+		Name:    "HGX H100",
+		Models:  []string{"NVIDIA H100 80GB HBM3"},
+		gpus:    8,
+		nvlinks: nvlinkAllToAll(8, links.EighteenNVLINKLinks),
+	},
+}
+
+// KnownSystems returns the catalog of known systems.
+func KnownSystems() []*KnownSystem {
+	return append([]*KnownSystem{}, knownSystems...)
+}
+
+// LookupSystem returns the known system with the specified name.
+func LookupSystem(name string) (*KnownSystem, bool) {
+	for _, s := range knownSystems {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// IdentifySystem returns the known system whose topology matches the
+// fingerprint of the devices.
+func IdentifySystem(d DeviceList) (*KnownSystem, bool) {
+	fingerprint := d.Fingerprint()
+	for _, s := range knownSystems {
+		if s.matches(fingerprint) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// Matches checks whether the devices have the topology of the system with
+// one of its GPU models.
+func (s *KnownSystem) Matches(d DeviceList) bool {
+	return s.matches(d.Fingerprint())
+}
+
+func (s *KnownSystem) matches(fingerprint string) bool {
+	for _, fp := range s.Fingerprints() {
+		if fp == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// Fingerprints returns the fingerprint of the system for each of its GPU
+// models.
+func (s *KnownSystem) Fingerprints() []string {
+	var fingerprints []string
+	for _, model := range s.Models {
+		fingerprints = append(fingerprints, s.Topology(model).Fingerprint())
+	}
+	return fingerprints
+}
+
+// Topology returns the devices of the system with the specified GPU model.
+// The GPUs are given placeholder UUIDs of the form GPU-<index>. Links are
+// recorded as shown by 'nvidia-smi topo -m': GPUs connected by NVLinks only
+// have their NVLinks recorded.
+func (s *KnownSystem) Topology(model string) DeviceList {
+	devices := make(DeviceList, s.gpus)
+	for i := range devices {
+		devices[i] = &Device{
+			nvlibDevice: nvlibDevice{UUID: fmt.Sprintf("GPU-%d", i)},
+			Index:       i,
+			Links:       make(map[int][]P2PLink),
+			Model:       model,
+		}
+	}
+
+	for i, gpu0 := range devices {
+		for _, gpu1 := range devices[i+1:] {
+			linkType, ok := s.nvlinks[[2]int{gpu0.Index, gpu1.Index}]
+			if !ok {
+				linkType = s.pcie
+			}
+			if linkType == links.P2PLinkUnknown {
+				continue
+			}
+			gpu0.Links[gpu1.Index] = []P2PLink{{gpu1, linkType}}
+			gpu1.Links[gpu0.Index] = []P2PLink{{gpu0, linkType}}
+		}
+	}
+
+	return devices
+}
@@ -0,0 +1,262 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import "sort"
+
+// Migration moves an allocation to a new set of GPUs. An allocation is the
+// set of GPUs allocated to a request group, or to an owner for GPUs that were
+// allocated without a group.
+type Migration struct {
+	// Group is the request group of the allocation, if any.
+	Group string
+	// Owner is the owner of the allocation. It is empty if the GPUs of the
+	// group belong to different owners.
+	Owner string
+	From  []*Device
+	To    []*Device
+	// Cost is the disruption cost of moving the allocation.
+	Cost float64
+}
+
+// MigrationPlan proposes a set of migrations that frees a set of GPUs for a
+// pending request. A plan is only a proposal; the allocator is not changed.
+type MigrationPlan struct {
+	// Target holds the GPUs the pending request would be allocated.
+	Target []*Device
+	// Score is the BestEffort score of the target GPUs.
+	Score      int
+	Migrations []Migration
+	// Moved is the number of GPUs that the migrated allocations give up.
+	Moved int
+	// Cost is the total disruption cost of the migrations.
+	Cost float64
+}
+
+// DisruptionCostFunc returns the cost of migrating the allocation of an
+// owner. For a group whose GPUs belong to different owners, 'owner' is
+// empty.
+type DisruptionCostFunc func(owner string, devices []*Device) float64
+
+type migrationPlanner struct {
+	minScore *int
+	cost     DisruptionCostFunc
+	maxPlans int
+
+	allocator *Allocator
+	size      int
+	// allocations maps each allocation to the GPUs it holds, and keys holds
+	// the allocations in sorted order.
+	allocations map[allocationKey][]*Device
+	keys        []allocationKey
+	// schedulable holds the GPUs that are not cordoned, whether or not they
+	// are allocated.
+	schedulable []*Device
+}
+
+// MigrationOption defines a functional option for PlanMigrations.
+type MigrationOption func(*migrationPlanner)
+
+// WithMinScore sets the lowest BestEffort score a target set must have. By
+// default, only target sets with the best score achievable on an empty node
+// are considered.
+func WithMinScore(score int) MigrationOption {
+	return func(p *migrationPlanner) {
+		p.minScore = &score
+	}
+}
+
+// WithDisruptionCost sets the function used to weigh the migration of each
+// allocation. By default, migrating an allocation costs 1 per GPU.
+func WithDisruptionCost(cost DisruptionCostFunc) MigrationOption {
+	return func(p *migrationPlanner) {
+		p.cost = cost
+	}
+}
+
+// WithMaxPlans limits the number of plans returned. By default, all plans are
+// returned.
+func WithMaxPlans(n int) MigrationOption {
+	return func(p *migrationPlanner) {
+		p.maxPlans = n
+	}
+}
+
+// PlanMigrations proposes ways to free a well connected set of 'size' GPUs
+// for a pending request by moving existing allocations. Each plan moves every
+// allocation that holds one of the target GPUs to other GPUs chosen by the
+// policy of the allocator. GPUs allocated to a request group move together
+// as one allocation; other GPUs move together with the rest of their owner's
+// GPUs. Anonymous allocations are never moved.
+//
+// Plans are ranked by the number of GPUs moved, then by disruption cost, then
+// by the score of the target set. A plan without migrations is returned first
+// if the free GPUs already hold a suitable set.
+func (a *Allocator) PlanMigrations(size int, opts ...MigrationOption) []*MigrationPlan {
+	p := &migrationPlanner{
+		cost:        func(owner string, devices []*Device) float64 { return float64(len(devices)) },
+		allocator:   a,
+		size:        size,
+		allocations: make(map[allocationKey][]*Device),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	for _, gpu := range a.allocated.SortedSlice() {
+		key := a.keyOf(gpu)
+		if _, exists := p.allocations[key]; !exists {
+			p.keys = append(p.keys, key)
+		}
+		p.allocations[key] = append(p.allocations[key], gpu)
+		if !a.cordoned[gpu.UUID] {
+			p.schedulable = append(p.schedulable, gpu)
+		}
+	}
+	p.schedulable = append(p.schedulable, a.available()...)
+	sort.Slice(p.schedulable, func(i, j int) bool {
+		return p.schedulable[i].Index < p.schedulable[j].Index
+	})
+	sort.Slice(p.keys, func(i, j int) bool {
+		if p.keys[i].group != p.keys[j].group {
+			return p.keys[i].group < p.keys[j].group
+		}
+		return p.keys[i].owner < p.keys[j].owner
+	})
+
+	return p.plan()
+}
+
+// plan evaluates every suitable target set and ranks the resulting plans.
+func (p *migrationPlanner) plan() []*MigrationPlan {
+	if p.size <= 0 || p.size > len(p.schedulable) {
+		return nil
+	}
+
+	minScore := 0
+	if p.minScore != nil {
+		minScore = *p.minScore
+	} else {
+		iterateGPUSets(p.schedulable, p.size, func(set []*Device) {
+			if score := calculateGPUSetScore(set); score > minScore {
+				minScore = score
+			}
+		})
+	}
+
+	var plans []*MigrationPlan
+	iterateGPUSets(p.schedulable, p.size, func(set []*Device) {
+		score := calculateGPUSetScore(set)
+		if score < minScore {
+			return
+		}
+		target := append([]*Device{}, set...)
+		if plan := p.planFor(target); plan != nil {
+			plan.Score = score
+			plans = append(plans, plan)
+		}
+	})
+
+	sort.SliceStable(plans, func(i, j int) bool {
+		if plans[i].Moved != plans[j].Moved {
+			return plans[i].Moved < plans[j].Moved
+		}
+		if plans[i].Cost != plans[j].Cost {
+			return plans[i].Cost < plans[j].Cost
+		}
+		return plans[i].Score > plans[j].Score
+	})
+
+	if p.maxPlans > 0 && len(plans) > p.maxPlans {
+		plans = plans[:p.maxPlans]
+	}
+	return plans
+}
+
+// planFor returns the migrations needed to free the target GPUs, or nil if
+// the displaced allocations cannot be placed elsewhere.
+func (p *migrationPlanner) planFor(target []*Device) *MigrationPlan {
+	targetSet := NewDeviceSet(target...)
+
+	// Find the allocations holding any of the target GPUs. Anonymous
+	// allocations cannot be moved.
+	var displaced []allocationKey
+	for _, key := range p.keys {
+		for _, gpu := range p.allocations[key] {
+			if targetSet.Contains(gpu) {
+				if key.anonymous() {
+					return nil
+				}
+				displaced = append(displaced, key)
+				break
+			}
+		}
+	}
+
+	// The displaced allocations may move to any free GPU, or to the GPUs they
+	// release outside the target set.
+	pool := NewDeviceSet()
+	for _, gpu := range p.allocator.available() {
+		if !targetSet.Contains(gpu) {
+			pool.Insert(gpu)
+		}
+	}
+	for _, key := range displaced {
+		for _, gpu := range p.allocations[key] {
+			if !targetSet.Contains(gpu) && !p.allocator.cordoned[gpu.UUID] {
+				pool.Insert(gpu)
+			}
+		}
+	}
+
+	// Place the largest allocations first, since they are the hardest to
+	// place.
+	sort.SliceStable(displaced, func(i, j int) bool {
+		return len(p.allocations[displaced[i]]) > len(p.allocations[displaced[j]])
+	})
+
+	plan := &MigrationPlan{
+		Target:     target,
+		Migrations: []Migration{},
+	}
+	for _, key := range displaced {
+		from := p.allocations[key]
+		to := p.allocator.policy.Allocate(pool.SortedSlice(), nil, len(from))
+		if len(to) != len(from) {
+			return nil
+		}
+		pool.Delete(to...)
+
+		owner := p.allocator.commonOwner(from)
+		migration := Migration{
+			Group: key.group,
+			Owner: owner,
+			From:  from,
+			To:    to,
+			Cost:  p.cost(owner, from),
+		}
+		toSet := NewDeviceSet(to...)
+		for _, gpu := range from {
+			if !toSet.Contains(gpu) {
+				plan.Moved++
+			}
+		}
+		plan.Cost += migration.Cost
+		plan.Migrations = append(plan.Migrations, migration)
+	}
+	return plan
+}
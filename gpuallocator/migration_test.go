@@ -0,0 +1,164 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newScatteredDGX1VoltaAllocator returns an allocator for a DGX-1 (Volta) in
+// which small jobs hold a GPU on each of the two NVLink quads.
+func newScatteredDGX1VoltaAllocator(t *testing.T) *Allocator {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+	require.NoError(t, allocator.AllocateSpecificFor("a", devices[1]))
+	require.NoError(t, allocator.AllocateSpecificFor("b", devices[5], devices[6]))
+	return allocator
+}
+
+func TestPlanMigrations(t *testing.T) {
+	allocator := newScatteredDGX1VoltaAllocator(t)
+
+	plans := allocator.PlanMigrations(4)
+	require.NotEmpty(t, plans)
+
+	// Moving the single GPU of owner a frees the first quad.
+	best := plans[0]
+	require.Equal(t, []int{0, 1, 2, 3}, indicesOf(best.Target))
+	require.Equal(t, 1, best.Moved)
+	require.Equal(t, 1.0, best.Cost)
+	require.Len(t, best.Migrations, 1)
+	require.Equal(t, "a", best.Migrations[0].Owner)
+	require.Equal(t, []int{1}, indicesOf(best.Migrations[0].From))
+	require.Subset(t, []int{4, 7}, indicesOf(best.Migrations[0].To))
+
+	// Freeing the second quad moves both GPUs of owner b.
+	last := plans[len(plans)-1]
+	require.Equal(t, []int{4, 5, 6, 7}, indicesOf(last.Target))
+	require.Equal(t, 2, last.Moved)
+
+	for i := 1; i < len(plans); i++ {
+		require.LessOrEqual(t, plans[i-1].Moved, plans[i].Moved)
+	}
+
+	// The planner does not change the allocator.
+	owner, allocated := allocator.Owner(allocator.GPUs[1])
+	require.True(t, allocated)
+	require.Equal(t, "a", owner)
+	require.Len(t, allocator.available(), 5)
+}
+
+func TestPlanMigrationsDisruptionCost(t *testing.T) {
+	allocator := newScatteredDGX1VoltaAllocator(t)
+
+	// Owner a is expensive to move, but moving fewer GPUs still wins.
+	expensive := func(owner string, devices []*Device) float64 {
+		if owner == "a" {
+			return 10
+		}
+		return 1
+	}
+	plans := allocator.PlanMigrations(4, WithDisruptionCost(expensive))
+	require.Equal(t, 10.0, plans[0].Cost)
+	require.Equal(t, []int{0, 1, 2, 3}, indicesOf(plans[0].Target))
+
+	// Among plans moving the same number of GPUs, the cheaper one wins.
+	allocator.Free(allocator.GPUs[6])
+	plans = allocator.PlanMigrations(4, WithDisruptionCost(expensive))
+	require.Equal(t, 1, plans[0].Moved)
+	require.Equal(t, 1.0, plans[0].Cost)
+	require.Equal(t, []int{4, 5, 6, 7}, indicesOf(plans[0].Target))
+}
+
+func TestPlanMigrationsWithoutMigrations(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+	require.NoError(t, allocator.AllocateSpecificFor("a", devices[0]))
+
+	plans := allocator.PlanMigrations(4, WithMaxPlans(1))
+	require.Len(t, plans, 1)
+	require.Equal(t, []int{4, 5, 6, 7}, indicesOf(plans[0].Target))
+	require.Empty(t, plans[0].Migrations)
+	require.Equal(t, 0, plans[0].Moved)
+}
+
+func TestPlanMigrationsUnmovable(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+
+	// Anonymous allocations are never moved.
+	require.NoError(t, allocator.AllocateSpecific(devices[0], devices[4]))
+	require.Empty(t, allocator.PlanMigrations(4))
+
+	// Lowering the score requirement admits less connected sets.
+	plans := allocator.PlanMigrations(4, WithMinScore(0))
+	require.NotEmpty(t, plans)
+	require.Equal(t, 0, plans[0].Moved)
+
+	// An 8 GPU job cannot be placed next to an anonymous allocation.
+	require.Empty(t, allocator.PlanMigrations(8, WithMinScore(0)))
+	require.Empty(t, allocator.PlanMigrations(0))
+}
+
+func TestPlanMigrationsNoRoom(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+	require.NoError(t, allocator.AllocateSpecificFor("a", devices[0], devices[1], devices[2], devices[3]))
+	require.NoError(t, allocator.AllocateSpecificFor("b", devices[4], devices[5], devices[6]))
+
+	// Owner a has nowhere to go, so its quad cannot be freed.
+	plans := allocator.PlanMigrations(4)
+	require.Empty(t, plans)
+}
+
+func TestPlanMigrationsByGroup(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+
+	// Owner a runs two independent groups, and group c spans two owners.
+	_, err := allocator.AllocateRequest(Request{Owner: "a", Group: "a1", Size: 1, Required: devices[1:2]})
+	require.NoError(t, err)
+	_, err = allocator.AllocateRequest(Request{Owner: "a", Group: "a2", Size: 1, Required: devices[3:4]})
+	require.NoError(t, err)
+	_, err = allocator.AllocateRequest(Request{Owner: "b", Group: "c", Size: 1, Required: devices[5:6]})
+	require.NoError(t, err)
+	_, err = allocator.AllocateRequest(Request{Owner: "d", Group: "c", Size: 1, Required: devices[6:7]})
+	require.NoError(t, err)
+
+	plans := allocator.PlanMigrations(4)
+	require.NotEmpty(t, plans)
+
+	// Freeing the first quad moves both groups of owner a separately.
+	best := plans[0]
+	require.Equal(t, []int{0, 1, 2, 3}, indicesOf(best.Target))
+	require.Len(t, best.Migrations, 2)
+	require.ElementsMatch(t, []string{"a1", "a2"}, []string{best.Migrations[0].Group, best.Migrations[1].Group})
+	for _, migration := range best.Migrations {
+		require.Equal(t, "a", migration.Owner)
+		require.Len(t, migration.From, 1)
+	}
+
+	// Freeing the second quad moves group c as a whole.
+	last := plans[len(plans)-1]
+	require.Equal(t, []int{4, 5, 6, 7}, indicesOf(last.Target))
+	require.Len(t, last.Migrations, 1)
+	require.Equal(t, "c", last.Migrations[0].Group)
+	require.Equal(t, "", last.Migrations[0].Owner)
+	require.Equal(t, []int{5, 6}, indicesOf(last.Migrations[0].From))
+}
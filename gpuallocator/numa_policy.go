@@ -0,0 +1,206 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"sort"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// LocalityHints describe the resources an allocation should be placed close
+// to, such as the CPUs handed out by a CPU manager or the RDMA NICs used for
+// multi-node communication.
+type LocalityHints struct {
+	// NUMANodes holds the NUMA nodes the allocation should be local to.
+	NUMANodes []int
+	// NICs holds the PCI bus IDs (e.g. 0000:3b:00.0) of network devices the
+	// allocation should be local to. Their NUMA nodes are looked up in the
+	// sysfs mount the GPUs were discovered from (see WithSysfsRoot).
+	NICs []string
+	// CPUs holds the CPUs the allocation should be local to. A GPU is local
+	// to these CPUs if its CPU affinity includes any of them.
+	CPUs CPUSet
+}
+
+// LocalityPolicy is implemented by policies that can take locality hints into
+// account when allocating GPUs.
+type LocalityPolicy interface {
+	Policy
+	// AllocateWithHints behaves like Allocate, but prefers GPUs that are
+	// local to the resources described by 'hints'.
+	AllocateWithHints(available []*Device, required []*Device, size int, hints LocalityHints) []*Device
+}
+
+type numaAlignedPolicy struct {
+	bestEffort Policy
+}
+
+// NewNUMAAlignedPolicy creates a new NUMAAlignedPolicy.
+func NewNUMAAlignedPolicy() LocalityPolicy {
+	return &numaAlignedPolicy{
+		bestEffort: NewBestEffortPolicy(),
+	}
+}
+
+// Allocate GPUs following the BestEffort policy, since no locality hints
+// are available.
+func (p *numaAlignedPolicy) Allocate(available []*Device, required []*Device, size int) []*Device {
+	return p.bestEffort.Allocate(available, required, size)
+}
+
+// AllocateWithHints finds a set of 'size' GPUs that is as close as possible to
+// the resources described by 'hints' and returns it.
+//
+// If enough GPUs share a NUMA node with the hinted resources, the allocation is
+// made from those GPUs alone using the BestEffort policy. Otherwise, every
+// candidate set is scored by the number of local GPUs it contains, with ties
+// broken by the BestEffort set score.
+func (p *numaAlignedPolicy) AllocateWithHints(available []*Device, required []*Device, size int, hints LocalityHints) []*Device {
+	if size <= 0 {
+		return []*Device{}
+	}
+
+	if len(available) < size {
+		return []*Device{}
+	}
+
+	if len(required) > size {
+		return []*Device{}
+	}
+
+	if !NewDeviceSet(available...).ContainsAll(required) {
+		return []*Device{}
+	}
+
+	locality := resolveLocality(hints, available)
+	if locality.isEmpty() {
+		return p.bestEffort.Allocate(available, required, size)
+	}
+
+	local := NewDeviceSet(required...)
+	for _, gpu := range available {
+		if locality.isLocal(gpu) {
+			local.Insert(gpu)
+		}
+	}
+	if len(local) >= size {
+		if allocated := p.bestEffort.Allocate(local.SortedSlice(), required, size); len(allocated) != 0 {
+			return allocated
+		}
+	}
+
+	var bestSet []*Device
+	bestLocalCount := -1
+	bestScore := -1
+	iterateGPUSets(available, size, func(set []*Device) {
+		if !gpuSetContainsAll(set, required) {
+			return
+		}
+		localCount := 0
+		for _, gpu := range set {
+			if locality.isLocal(gpu) {
+				localCount++
+			}
+		}
+		if localCount < bestLocalCount {
+			return
+		}
+		score := calculateGPUSetScore(set)
+		if localCount > bestLocalCount || score > bestScore {
+			bestSet = append([]*Device{}, set...)
+			bestLocalCount = localCount
+			bestScore = score
+		}
+	})
+
+	if bestSet == nil {
+		return []*Device{}
+	}
+	return bestSet
+}
+
+// locality holds the resolved form of a set of LocalityHints.
+type locality struct {
+	nodes map[int]bool
+	cpus  CPUSet
+}
+
+// resolveLocality returns the NUMA nodes and CPUs covered by the hints. The
+// NICs are looked up in the sysfs mount the GPUs were discovered from, or in
+// the host sysfs if it is unknown. NICs without NUMA information are ignored.
+func resolveLocality(hints LocalityHints, gpus []*Device) locality {
+	sysfs := links.DefaultSysfs
+	for _, gpu := range gpus {
+		if gpu.sysfs != "" {
+			sysfs = gpu.sysfs
+			break
+		}
+	}
+
+	nodes := make(map[int]bool)
+	for _, node := range hints.NUMANodes {
+		nodes[node] = true
+	}
+	for _, nic := range hints.NICs {
+		if node := sysfs.NumaNode(nic); node >= 0 {
+			nodes[int(node)] = true
+		}
+	}
+	return locality{nodes: nodes, cpus: hints.CPUs}
+}
+
+// isEmpty checks whether the locality places no constraints on an allocation.
+func (l locality) isEmpty() bool {
+	return len(l.nodes) == 0 && len(l.cpus) == 0
+}
+
+// isLocal checks whether a GPU is attached to one of the NUMA nodes or is
+// local to one of the CPUs of the locality.
+func (l locality) isLocal(gpu *Device) bool {
+	if gpu.CPUAffinity != nil && l.nodes[int(*gpu.CPUAffinity)] {
+		return true
+	}
+	return gpu.CPUs.Intersects(l.cpus)
+}
+
+// DiscoverRDMANICs returns the PCI bus IDs of the RDMA NICs found under the
+// specified sysfs mount, sorted by device name. An empty root selects the
+// host sysfs.
+func DiscoverRDMANICs(sysfsRoot string) ([]string, error) {
+	sysfs := links.DefaultSysfs
+	if sysfsRoot != "" {
+		sysfs = links.Sysfs(sysfsRoot)
+	}
+
+	devices, err := sysfs.RDMADevices()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var nics []string
+	for _, name := range names {
+		nics = append(nics, devices[name])
+	}
+	return nics, nil
+}
@@ -0,0 +1,187 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+	"github.com/NVIDIA/go-gpuallocator/internal/sysfstest"
+)
+
+// setNUMANodes assigns the NUMA node of each GPU in the node.
+func (n TestNode) setNUMANodes(nodes ...uint) TestNode {
+	for i := range n {
+		node := nodes[i]
+		n[i].CPUAffinity = &node
+	}
+	return n
+}
+
+func TestNUMAAlignedAllocateWithHints(t *testing.T) {
+	devices := NewDGX1VoltaNode().setNUMANodes(0, 0, 0, 0, 1, 1, 1, 1).Devices()
+
+	sysfs := sysfstest.New(t).
+		AddPCIDevice("0000:3a:00.0", "", nil).
+		AddPCIDevice("0000:3b:00.0", "0000:3a:00.0", sysfstest.Attributes{"numa_node": "0"}).
+		AddRDMADevice("mlx5_0", "0000:3b:00.0")
+
+	nics, err := DiscoverRDMANICs(sysfs.Root)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0000:3b:00.0"}, nics)
+
+	for _, d := range devices {
+		d.sysfs = links.Sysfs(sysfs.Root)
+	}
+	policy := NewNUMAAlignedPolicy()
+
+	testCases := []struct {
+		description string
+		available   []int
+		required    []int
+		size        int
+		hints       LocalityHints
+		expected    []int
+	}{
+		{
+			description: "no hints falls back to best effort",
+			available:   []int{0, 1, 2, 3, 4, 5, 6, 7},
+			size:        8,
+			expected:    []int{0, 1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			description: "NUMA node hint",
+			available:   []int{0, 1, 2, 3, 4, 5, 6, 7},
+			size:        2,
+			hints:       LocalityHints{NUMANodes: []int{1}},
+			expected:    []int{4, 7},
+		},
+		{
+			description: "NIC hint resolved through sysfs",
+			available:   []int{0, 1, 2, 3, 4, 5, 6, 7},
+			size:        2,
+			hints:       LocalityHints{NICs: nics},
+			expected:    []int{0, 3},
+		},
+		{
+			description: "not enough local GPUs spills to remote GPUs",
+			available:   []int{0, 1, 4, 5, 6},
+			size:        3,
+			hints:       LocalityHints{NUMANodes: []int{0}},
+			expected:    []int{0, 1, 4},
+		},
+		{
+			description: "required remote GPU is kept",
+			available:   []int{0, 1, 2, 3, 4, 5, 6, 7},
+			required:    []int{4},
+			size:        2,
+			hints:       LocalityHints{NUMANodes: []int{0}},
+			expected:    []int{0, 4},
+		},
+		{
+			description: "unknown NIC is ignored",
+			available:   []int{0, 1, 2, 3, 4, 5, 6, 7},
+			size:        8,
+			hints:       LocalityHints{NICs: []string{"0000:ff:00.0"}},
+			expected:    []int{0, 1, 2, 3, 4, 5, 6, 7},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			available := GetDevicesFromIndices(devices, tc.available)
+			required := GetDevicesFromIndices(devices, tc.required)
+
+			allocated := policy.AllocateWithHints(available, required, tc.size, tc.hints)
+			sortGPUSet(allocated)
+			require.Equal(t, tc.expected, indicesOf(allocated))
+		})
+	}
+}
+
+func TestNUMAAlignedUsesDiscoverySysfs(t *testing.T) {
+	sysfs := sysfstest.New(t).
+		AddPCIDevice("0000:00:01.0", "", nil).
+		AddPCIDevice("0000:3b:00.0", "0000:00:01.0", sysfstest.Attributes{"numa_node": "0"}).
+		AddPCIDevice("0000:86:00.0", "0000:00:01.0", sysfstest.Attributes{"numa_node": "1"}).
+		AddPCIDevice("0000:87:00.0", "0000:00:01.0", sysfstest.Attributes{"numa_node": "1"}).
+		AddRDMADevice("mlx5_0", "0000:87:00.0")
+
+	mocks := []*mock.Device{
+		newMockDevice(0, "00000000:3B:00.0"),
+		newMockDevice(1, "00000000:86:00.0"),
+	}
+	for _, d := range mocks {
+		d.GetTopologyCommonAncestorFunc = func(nvml.Device) (nvml.GpuTopologyLevel, nvml.Return) {
+			return nvml.TOPOLOGY_SYSTEM, nvml.SUCCESS
+		}
+	}
+	devices, err := NewDevices(WithNvmlLib(newMockNVML(mocks...)), WithSysfsRoot(sysfs.Root))
+	require.NoError(t, err)
+
+	nics, err := DiscoverRDMANICs(sysfs.Root)
+	require.NoError(t, err)
+
+	allocated := NewNUMAAlignedPolicy().AllocateWithHints(devices, nil, 1, LocalityHints{NICs: nics})
+	require.Equal(t, []int{1}, indicesOf(allocated))
+}
+
+func TestAllocatorAllocateWithHints(t *testing.T) {
+	devices := NewDGX1VoltaNode().setNUMANodes(0, 0, 0, 0, 1, 1, 1, 1).Devices()
+
+	allocator := newAllocatorFrom(devices, NewNUMAAlignedPolicy())
+	allocated := allocator.AllocateWithHints(4, LocalityHints{NUMANodes: []int{1}})
+	sortGPUSet(allocated)
+	require.Equal(t, []int{4, 5, 6, 7}, indicesOf(allocated))
+
+	allocator.Free(allocated...)
+	for _, d := range devices {
+		d.CPUs = NewCPUSet(int(*d.CPUAffinity)*32, int(*d.CPUAffinity)*32+31)
+	}
+	allocated = allocator.AllocateWithHints(2, LocalityHints{CPUs: NewCPUSet(0)})
+	sortGPUSet(allocated)
+	require.Equal(t, []int{0, 3}, indicesOf(allocated))
+
+	// Policies without locality support ignore the hints.
+	allocator = newAllocatorFrom(devices, NewSimplePolicy())
+	allocated = allocator.AllocateWithHints(2, LocalityHints{NUMANodes: []int{1}})
+	require.Equal(t, []int{0, 1}, indicesOf(allocated))
+}
+
+func TestAllocatorAllocateWithHintsFor(t *testing.T) {
+	devices := NewDGX1VoltaNode().setNUMANodes(0, 0, 0, 0, 1, 1, 1, 1).Devices()
+
+	for _, policy := range []Policy{NewNUMAAlignedPolicy(), NewSimplePolicy()} {
+		allocator := newAllocatorFrom(devices, policy)
+		allocated := allocator.AllocateWithHintsFor("job-a", 1, LocalityHints{NUMANodes: []int{1}})
+		require.Len(t, allocated, 1)
+
+		owner, ok := allocator.Owner(allocated[0])
+		require.True(t, ok)
+		require.Equal(t, "job-a", owner)
+
+		// Owned allocations can be preempted by other requests.
+		plan, err := allocator.PlanPreemption(Request{Owner: "job-b", Priority: 1, Size: 8})
+		require.NoError(t, err)
+		require.Len(t, plan.Victims, 1)
+		require.Equal(t, "job-a", plan.Victims[0].Owner)
+	}
+}
@@ -19,12 +19,25 @@ package gpuallocator
 import (
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
 )
 
 // deviceListBuilder stores the options required to build a list of linked devices.
 type deviceListBuilder struct {
 	nvmllib   nvml.Interface
 	devicelib device.Interface
+	sysfs     links.Sysfs
+	// repairTopology repairs an inconsistent link table instead of failing.
+	repairTopology bool
+	// concurrency is the maximum number of concurrent device queries.
+	concurrency int
+	// stats receives statistics about the discovery if set.
+	stats *DiscoveryStats
+	// expected is the baseline the discovered devices are verified against,
+	// with the result stored in verification.
+	expected     DeviceList
+	verification *VerificationReport
 }
 
 // Option defines a type for functional options for constructing device lists.
@@ -43,3 +56,45 @@ func WithDeviceLib(devicelib device.Interface) Option {
 		o.devicelib = devicelib
 	}
 }
+
+// WithSysfsRoot provides an option to set the root of the sysfs mount used to
+// discover NUMA and PCI information (e.g. /host/sys in a container).
+func WithSysfsRoot(root string) Option {
+	return func(o *deviceListBuilder) {
+		o.sysfs = links.Sysfs(root)
+	}
+}
+
+// WithTopologyRepair provides an option to repair an inconsistent link table
+// instead of returning an error. See DeviceList.RepairTopology().
+func WithTopologyRepair() Option {
+	return func(o *deviceListBuilder) {
+		o.repairTopology = true
+	}
+}
+
+// WithDiscoveryConcurrency provides an option to set the maximum number of
+// devices queried concurrently during discovery. It defaults to GOMAXPROCS.
+func WithDiscoveryConcurrency(concurrency int) Option {
+	return func(o *deviceListBuilder) {
+		o.concurrency = concurrency
+	}
+}
+
+// WithDiscoveryStats provides an option to receive statistics about the
+// discovery, such as the number of NVML queries made.
+func WithDiscoveryStats(stats *DiscoveryStats) Option {
+	return func(o *deviceListBuilder) {
+		o.stats = stats
+	}
+}
+
+// WithExpectedTopology provides an option to verify the discovered devices
+// against an expected baseline, such as a snapshot or the topology of a known
+// system. The result is stored in 'report'. See DeviceList.Verify().
+func WithExpectedTopology(expected DeviceList, report *VerificationReport) Option {
+	return func(o *deviceListBuilder) {
+		o.expected = expected
+		o.verification = report
+	}
+}
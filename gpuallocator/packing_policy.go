@@ -0,0 +1,149 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+// maxExactFutureSize is the largest number of remaining GPUs for which the
+// Packing policy computes the best achievable set scores exactly. Beyond it,
+// the scores are estimated greedily, as they are computed for every
+// candidate set.
+const maxExactFutureSize = 12
+
+type packingPolicy struct {
+	bestEffort Policy
+}
+
+// NewPackingPolicy creates a new Packing policy. The Packing policy protects
+// future large requests: it places small requests on the GPUs whose loss
+// least reduces the quality of the largest sets that remain, for example by
+// filling partially used NVLink islands first. Large requests are allocated
+// following the BestEffort policy.
+func NewPackingPolicy() Policy {
+	return &packingPolicy{
+		bestEffort: NewBestEffortPolicy(),
+	}
+}
+
+// Allocate finds a set of 'size' GPUs to allocate from a list of available
+// GPU devices and returns it.
+//
+// Requests for at most half of the available GPUs are considered small. For
+// these, the candidate sets are those that score at least as well as the set
+// chosen by the BestEffort policy. Each candidate is judged by the best
+// BestEffort scores that the remaining GPUs can still achieve for sets of 2,
+// 4, 8, ... GPUs. The scores are compared from the largest size down, so
+// that the largest sets are protected first. Ties are broken by the score of
+// the candidate set itself and then in favor of the GPUs with the lowest
+// indices.
+func (p *packingPolicy) Allocate(available []*Device, required []*Device, size int) []*Device {
+	if size <= 0 {
+		return []*Device{}
+	}
+
+	if len(available) < size {
+		return []*Device{}
+	}
+
+	if len(required) > size {
+		return []*Device{}
+	}
+
+	// The BestEffort allocation sets the bar for the score of the request.
+	baseline := p.bestEffort.Allocate(available, required, size)
+	if len(baseline) == 0 || 2*size > len(available) {
+		return baseline
+	}
+	minScore := calculateGPUSetScore(baseline)
+
+	// The pair scores are shared by the remaining GPUs of all candidates.
+	pairScores := pairScoreMatrix(available, calculateGPUPairScore)
+
+	var bestSet []*Device
+	var bestFuture []int
+	bestScore := 0
+	iterateGPUSets(available, size, func(set []*Device) {
+		if !gpuSetContainsAll(set, required) {
+			return
+		}
+		score := calculateGPUSetScore(set)
+		if score < minScore {
+			return
+		}
+
+		var remaining []int
+		for i, gpu := range available {
+			if !gpuSetContains(set, gpu) {
+				remaining = append(remaining, i)
+			}
+		}
+		future := futureScores(pairScores, remaining)
+
+		if bestSet == nil || compareScores(future, bestFuture) > 0 ||
+			(compareScores(future, bestFuture) == 0 && score > bestScore) {
+			bestSet = append([]*Device{}, set...)
+			bestFuture = future
+			bestScore = score
+		}
+	})
+
+	if bestSet == nil {
+		return []*Device{}
+	}
+	return bestSet
+}
+
+// futureScores returns the best scores achievable by the GPUs with the given
+// indices into the pair scores for sets of 2, 4, 8, ... GPUs, starting with
+// the largest size.
+func futureScores(pairScores [][]int, gpus []int) []int {
+	scores := make([][]int, len(gpus))
+	for i, u := range gpus {
+		scores[i] = make([]int, len(gpus))
+		for j, v := range gpus {
+			scores[i][j] = pairScores[u][v]
+		}
+	}
+
+	var best []int
+	if len(gpus) > maxExactFutureSize {
+		best = greedyBestScores(scores)
+	} else {
+		best = exactBestScores(scores)
+	}
+
+	var sizes []int
+	for size := 2; size <= len(gpus); size *= 2 {
+		sizes = append(sizes, size)
+	}
+
+	var future []int
+	for i := len(sizes) - 1; i >= 0; i-- {
+		future = append(future, best[sizes[i]])
+	}
+	return future
+}
+
+// compareScores compares two lists of scores of the same length
+// lexicographically. It returns a positive number if 'a' is better, a
+// negative number if 'b' is better and 0 if they are equal.
+func compareScores(a []int, b []int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return 0
+}
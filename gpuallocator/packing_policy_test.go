@@ -0,0 +1,144 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackingPolicyAllocate(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+
+	tests := []PolicyAllocTest{
+		{
+			"Single GPU leaves the NVLink quad intact",
+			devices,
+			[]int{0, 1, 2, 3, 4},
+			[]int{},
+			1,
+			[]int{4},
+		},
+		{
+			"Single GPU fills the partially used quad",
+			devices,
+			[]int{0, 1, 2, 4, 5, 6, 7},
+			[]int{},
+			1,
+			[]int{0},
+		},
+		{
+			"Pair scores as well as with BestEffort",
+			devices,
+			[]int{1, 2, 3, 4, 5, 6, 7},
+			[]int{},
+			2,
+			[]int{2, 3},
+		},
+		{
+			"Required GPU",
+			devices,
+			[]int{0, 1, 2, 3, 4, 5, 6, 7},
+			[]int{5},
+			1,
+			[]int{5},
+		},
+		{
+			"Large requests follow BestEffort",
+			devices,
+			[]int{0, 1, 2, 3, 4, 5, 6, 7},
+			[]int{},
+			6,
+			[]int{0, 1, 2, 3, 4, 5},
+		},
+		{
+			"Not enough GPUs",
+			devices,
+			[]int{0},
+			[]int{},
+			2,
+			[]int{},
+		},
+	}
+
+	RunPolicyAllocTests(t, NewPackingPolicy(), tests)
+}
+
+func TestPackingPolicyLargeNode(t *testing.T) {
+	devices := newNVSwitchNode().Devices()
+
+	// The 15 remaining GPUs exceed maxExactFutureSize, so their future
+	// scores are estimated greedily.
+	allocated := NewPackingPolicy().Allocate(devices, nil, 1)
+	require.Len(t, allocated, 1)
+}
+
+func TestFutureScores(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	pairScores := pairScoreMatrix(devices, calculateGPUPairScore)
+
+	best := bestSetScores(devices[2:])
+	require.Equal(t, []int{best[4], best[2]}, futureScores(pairScores, []int{2, 3, 4, 5, 6, 7}))
+}
+
+// newMixedWorkload returns a DGX-1 (Volta) and a trace of mostly small jobs,
+// in which jobs of 2 and 4 GPUs need the best connected sets of their size.
+func newMixedWorkload(jobs int) (DeviceList, Trace) {
+	devices := NewDGX1VoltaNode().Devices()
+	best := bestSetScores(devices)
+	trace := GenerateTrace(1, TraceConfig{
+		Jobs:             jobs,
+		Sizes:            []int{1, 1, 2, 4},
+		MeanInterarrival: 1,
+		MeanDuration:     2,
+		MinScores:        map[int]int{2: best[2], 4: best[4]},
+	})
+	return devices, trace
+}
+
+func TestPackingPolicyLowersFailureRate(t *testing.T) {
+	devices, trace := newMixedWorkload(500)
+	results := Simulate(devices, map[string]Policy{
+		"besteffort": NewBestEffortPolicy(),
+		"packing":    NewPackingPolicy(),
+	}, trace)
+
+	require.Equal(t, "besteffort", results[0].Policy)
+	require.Equal(t, "packing", results[1].Policy)
+	require.Less(t, results[1].FailureRate, results[0].FailureRate)
+}
+
+func BenchmarkMixedWorkload(b *testing.B) {
+	devices, trace := newMixedWorkload(500)
+	policies := map[string]Policy{
+		"simple":     NewSimplePolicy(),
+		"besteffort": NewBestEffortPolicy(),
+		"packing":    NewPackingPolicy(),
+	}
+
+	for name, policy := range policies {
+		b.Run(name, func(b *testing.B) {
+			var result *SimulationResult
+			for i := 0; i < b.N; i++ {
+				result = Simulate(devices, map[string]Policy{name: policy}, trace)[0]
+			}
+			b.ReportMetric(result.FailureRate, "failure-rate")
+			b.ReportMetric(result.AverageFragmentation, "fragmentation")
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// PCIeSwitch returns the upstream port of the PCIe switch the device is
+// attached to, or nil if the device is not behind a switch or its place in
+// the PCIe hierarchy is unknown.
+func (d *Device) PCIeSwitch() *links.PCIeNode {
+	if d.PCIe == nil {
+		return nil
+	}
+	return d.PCIe.Switch()
+}
+
+// PCIeDowntrained returns true if any PCIe link between the device and its
+// root complex runs below its maximum width.
+func (d *Device) PCIeDowntrained() bool {
+	if d.PCIe == nil {
+		return false
+	}
+	return d.PCIe.PathDowntrained()
+}
+
+// GroupByPCIeSwitch groups the devices by the PCIe switch they are attached
+// to. Devices that are not behind a switch are grouped under a nil key.
+func (d DeviceList) GroupByPCIeSwitch() map[*links.PCIeNode]DeviceList {
+	groups := make(map[*links.PCIeNode]DeviceList)
+	for _, device := range d {
+		sw := device.PCIeSwitch()
+		groups[sw] = append(groups[sw], device)
+	}
+	return groups
+}
+
+// Downtrained returns the devices with a downtrained PCIe link on the path
+// to their root complex.
+func (d DeviceList) Downtrained() DeviceList {
+	var downtrained DeviceList
+	for _, device := range d {
+		if device.PCIeDowntrained() {
+			downtrained = append(downtrained, device)
+		}
+	}
+	return downtrained
+}
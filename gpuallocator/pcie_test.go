@@ -0,0 +1,68 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+	"github.com/NVIDIA/go-gpuallocator/internal/sysfstest"
+)
+
+func TestGroupByPCIeSwitch(t *testing.T) {
+	link := func(speed string, width ...string) sysfstest.Attributes {
+		current := "16"
+		if len(width) != 0 {
+			current = width[0]
+		}
+		return sysfstest.Attributes{
+			"current_link_speed": speed,
+			"current_link_width": current,
+			"max_link_speed":     "32.0 GT/s PCIe",
+			"max_link_width":     "16",
+		}
+	}
+	sysfs := sysfstest.New(t).
+		AddPCIDevice("0000:00:01.0", "", link("32.0 GT/s PCIe")).
+		AddPCIDevice("0000:01:00.0", "0000:00:01.0", link("32.0 GT/s PCIe")).
+		AddPCIDevice("0000:02:00.0", "0000:01:00.0", link("32.0 GT/s PCIe")).
+		AddPCIDevice("0000:02:01.0", "0000:01:00.0", link("32.0 GT/s PCIe")).
+		AddPCIDevice("0000:03:00.0", "0000:02:00.0", link("32.0 GT/s PCIe")).
+		AddPCIDevice("0000:04:00.0", "0000:02:01.0", link("32.0 GT/s PCIe", "8")).
+		AddPCIDevice("0000:00:02.0", "", link("32.0 GT/s PCIe")).
+		AddPCIDevice("0000:05:00.0", "0000:00:02.0", link("2.5 GT/s PCIe"))
+
+	busIDs := []string{"0000:03:00.0", "0000:04:00.0", "0000:05:00.0"}
+	tree := links.Sysfs(sysfs.Root).PCIeTree(busIDs)
+
+	devices := DeviceList(New4xRTX8000Node().Devices()[:3])
+	for i, d := range devices {
+		d.PCIe = tree.Node(busIDs[i])
+	}
+
+	groups := devices.GroupByPCIeSwitch()
+	require.Len(t, groups, 2)
+	require.Equal(t, []int{0, 1}, indicesOf(groups[devices[0].PCIeSwitch()]))
+	require.Equal(t, []int{2}, indicesOf(groups[nil]))
+
+	// GPU 2 is idle at Gen1 speed, which is not a downtrained link.
+	require.Equal(t, []int{1}, indicesOf(devices.Downtrained()))
+	require.Contains(t, devices[1].Details(), "PCIe Link: Gen5 x8 (max Gen5 x16)")
+	require.Contains(t, devices[2].Details(), "PCIe Link: Gen1 x16 (max Gen5 x16)")
+}
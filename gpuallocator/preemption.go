@@ -0,0 +1,245 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultPriority is the priority of GPUs allocated without a Request, such
+// as through AllocateFor or AllocateSpecificFor, and of requests that do not
+// set one. These allocations may be preempted by any request of a higher
+// priority.
+const DefaultPriority = 0
+
+// Victim is an allocation that must be freed for a preemption plan. An
+// allocation is the set of GPUs allocated to a request group, or to an owner
+// for GPUs that were allocated without a group.
+type Victim struct {
+	// Group is the request group of the allocation, if any.
+	Group string
+	// Owner is the owner of the allocation. It is empty if the GPUs of the
+	// group belong to different owners.
+	Owner string
+	// Priority is the highest priority of the GPUs of the allocation.
+	Priority int
+	Devices  []*Device
+}
+
+// key returns the key of the allocation of the victim.
+func (v Victim) key() allocationKey {
+	if v.Group != "" {
+		return allocationKey{group: v.Group}
+	}
+	return allocationKey{owner: v.Owner}
+}
+
+// PreemptionPlan describes how a request is placed by preempting
+// lower-priority allocations.
+type PreemptionPlan struct {
+	Request Request
+	// Devices holds the GPUs the request is allocated when the plan is
+	// committed.
+	Devices []*Device
+	// Score is the BestEffort score of the GPUs of the request.
+	Score int
+	// Victims holds the allocations that must be freed before the plan is
+	// committed. It is empty if the request fits into the free GPUs.
+	Victims []Victim
+}
+
+// Preempted returns the number of GPUs held by the victims of the plan.
+func (p *PreemptionPlan) Preempted() int {
+	count := 0
+	for _, victim := range p.Victims {
+		count += len(victim.Devices)
+	}
+	return count
+}
+
+// PlanPreemption plans the placement of a request that may preempt
+// allocations of a lower priority. If the policy of the allocator can place
+// the request on the free GPUs, the plan has no victims. Otherwise, the plan
+// places the request on a set of GPUs that scores as well as the set the
+// policy would choose if all lower-priority allocations were gone, and that
+// preempts as few GPUs as possible. Ties are broken by the number of victims,
+// then by their priorities and then by the score of the set.
+//
+// Each victim loses its whole allocation: all GPUs of its request group, or
+// all GPUs of its owner that were allocated without a group. GPUs without a
+// recorded priority have DefaultPriority. Anonymous allocations are never
+// preempted. The allocator is not changed: the caller frees the victims and
+// then calls CommitPreemption.
+func (a *Allocator) PlanPreemption(request Request) (*PreemptionPlan, error) {
+	if request.Size <= 0 {
+		return nil, fmt.Errorf("invalid size %d", request.Size)
+	}
+
+	// Collect the allocations that the request may preempt.
+	allocations := make(map[allocationKey][]*Device)
+	priorities := make(map[allocationKey]int)
+	for _, gpu := range a.allocated.SortedSlice() {
+		key := a.keyOf(gpu)
+		allocations[key] = append(allocations[key], gpu)
+		if priority := a.priority(gpu); len(allocations[key]) == 1 || priority > priorities[key] {
+			priorities[key] = priority
+		}
+	}
+	preemptible := make(map[*Device]allocationKey)
+	candidates := NewDeviceSet(a.available()...)
+	for key, devices := range allocations {
+		if key.anonymous() || priorities[key] >= request.Priority {
+			continue
+		}
+		for _, gpu := range devices {
+			preemptible[gpu] = key
+			if !a.cordoned[gpu.UUID] {
+				candidates.Insert(gpu)
+			}
+		}
+	}
+
+	var required []*Device
+	for _, gpu := range request.Required {
+		current, ok := candidates[gpu.UUID]
+		if !ok {
+			return nil, fmt.Errorf("device '%v' is unavailable for allocation, even by preemption", gpu)
+		}
+		required = append(required, current)
+	}
+
+	// Place the request without preemption if possible.
+	available := NewDeviceSet(a.available()...)
+	if available.ContainsAll(required) {
+		if devices := a.policy.Allocate(a.available(), required, request.Size); len(devices) == request.Size {
+			plan := &PreemptionPlan{
+				Request: request,
+				Devices: devices,
+				Score:   calculateGPUSetScore(devices),
+				Victims: []Victim{},
+			}
+			return plan, nil
+		}
+	}
+
+	ideal := a.policy.Allocate(candidates.SortedSlice(), required, request.Size)
+	if len(ideal) != request.Size {
+		return nil, fmt.Errorf("unable to allocate %d GPUs, even by preemption", request.Size)
+	}
+	minScore := calculateGPUSetScore(ideal)
+
+	var best *PreemptionPlan
+	iterateGPUSets(candidates.SortedSlice(), request.Size, func(set []*Device) {
+		if !gpuSetContainsAll(set, required) {
+			return
+		}
+		score := calculateGPUSetScore(set)
+		if score < minScore {
+			return
+		}
+
+		plan := &PreemptionPlan{
+			Request: request,
+			Devices: append([]*Device{}, set...),
+			Score:   score,
+			Victims: []Victim{},
+		}
+		seen := make(map[allocationKey]bool)
+		for _, gpu := range set {
+			key, ok := preemptible[gpu]
+			if !ok || seen[key] {
+				continue
+			}
+			seen[key] = true
+			plan.Victims = append(plan.Victims, Victim{
+				Group:    key.group,
+				Owner:    a.commonOwner(allocations[key]),
+				Priority: priorities[key],
+				Devices:  allocations[key],
+			})
+		}
+		sort.Slice(plan.Victims, func(i, j int) bool {
+			if plan.Victims[i].Group != plan.Victims[j].Group {
+				return plan.Victims[i].Group < plan.Victims[j].Group
+			}
+			return plan.Victims[i].Owner < plan.Victims[j].Owner
+		})
+
+		if best == nil || lessDisruptive(plan, best) {
+			best = plan
+		}
+	})
+
+	if best == nil {
+		return nil, fmt.Errorf("unable to allocate %d GPUs, even by preemption", request.Size)
+	}
+	return best, nil
+}
+
+// lessDisruptive returns true if plan 'a' is preferred over plan 'b'.
+func lessDisruptive(a *PreemptionPlan, b *PreemptionPlan) bool {
+	if a.Preempted() != b.Preempted() {
+		return a.Preempted() < b.Preempted()
+	}
+	if len(a.Victims) != len(b.Victims) {
+		return len(a.Victims) < len(b.Victims)
+	}
+	if victimPriority(a) != victimPriority(b) {
+		return victimPriority(a) < victimPriority(b)
+	}
+	return a.Score > b.Score
+}
+
+// priority returns the priority recorded for an allocated GPU, or
+// DefaultPriority if none was recorded.
+func (a *Allocator) priority(gpu *Device) int {
+	if priority, ok := a.priorities[gpu.UUID]; ok {
+		return priority
+	}
+	return DefaultPriority
+}
+
+// victimPriority returns the sum of the priorities of the victims of a plan.
+func victimPriority(plan *PreemptionPlan) int {
+	sum := 0
+	for _, victim := range plan.Victims {
+		sum += victim.Priority
+	}
+	return sum
+}
+
+// CommitPreemption allocates the GPUs of a preemption plan to the owner of
+// its request. It returns an error and leaves the allocator unchanged if any
+// of the victims still holds its GPUs or if the GPUs of the plan have been
+// allocated in the meantime.
+func (a *Allocator) CommitPreemption(plan *PreemptionPlan) ([]*Device, error) {
+	for _, victim := range plan.Victims {
+		for _, gpu := range victim.Devices {
+			if a.allocated.Contains(gpu) && a.keyOf(gpu) == victim.key() {
+				return nil, fmt.Errorf("device '%v' is still allocated to victim %q", gpu, victim.key())
+			}
+		}
+	}
+
+	if err := a.AllocateSpecificFor(plan.Request.Owner, plan.Devices...); err != nil {
+		return nil, err
+	}
+	a.recordRequest(plan.Request, plan.Devices...)
+
+	return plan.Devices, nil
+}
@@ -0,0 +1,187 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newBusyDGX1VoltaAllocator returns an allocator for a DGX-1 (Volta) in which
+// every GPU is allocated. The second NVLink quad is held by a guaranteed job.
+func newBusyDGX1VoltaAllocator(t *testing.T) *Allocator {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+	requests := []Request{
+		{Owner: "batch-a", Priority: 1, Required: devices[0:1]},
+		{Owner: "batch-b", Priority: 1, Required: devices[1:3]},
+		{Owner: "batch-c", Priority: 0, Required: devices[3:4]},
+		{Owner: "guaranteed", Priority: 10, Required: devices[4:8]},
+	}
+	for _, request := range requests {
+		request.Size = len(request.Required)
+		_, err := allocator.AllocateRequest(request)
+		require.NoError(t, err)
+	}
+	require.Empty(t, allocator.available())
+	return allocator
+}
+
+func TestPlanPreemption(t *testing.T) {
+	allocator := newBusyDGX1VoltaAllocator(t)
+
+	// The cheapest victim is the single GPU job with the lowest priority.
+	plan, err := allocator.PlanPreemption(Request{Owner: "training", Priority: 5, Size: 1})
+	require.NoError(t, err)
+	require.Equal(t, []int{3}, indicesOf(plan.Devices))
+	require.Len(t, plan.Victims, 1)
+	require.Equal(t, "batch-c", plan.Victims[0].Owner)
+	require.Equal(t, 1, plan.Preempted())
+
+	// A quad preempts all batch jobs rather than settle for a split set.
+	plan, err = allocator.PlanPreemption(Request{Owner: "training", Priority: 5, Size: 4})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3}, indicesOf(plan.Devices))
+	require.Equal(t, 4, plan.Preempted())
+	var victims []string
+	for _, victim := range plan.Victims {
+		victims = append(victims, victim.Owner)
+	}
+	require.Equal(t, []string{"batch-a", "batch-b", "batch-c"}, victims)
+
+	// Planning does not change the allocator.
+	require.Empty(t, allocator.available())
+}
+
+func TestPlanPreemptionByGroup(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+	requests := []Request{
+		{Owner: "batch", Group: "small", Priority: 1, Required: devices[0:1]},
+		{Owner: "batch", Group: "large", Priority: 1, Required: devices[1:4]},
+		{Owner: "guaranteed", Priority: 10, Required: devices[4:8]},
+	}
+	for _, request := range requests {
+		request.Size = len(request.Required)
+		_, err := allocator.AllocateRequest(request)
+		require.NoError(t, err)
+	}
+
+	// Only the smaller group of the owner is preempted.
+	plan, err := allocator.PlanPreemption(Request{Owner: "training", Priority: 5, Size: 1})
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, indicesOf(plan.Devices))
+	require.Len(t, plan.Victims, 1)
+	require.Equal(t, "small", plan.Victims[0].Group)
+	require.Equal(t, "batch", plan.Victims[0].Owner)
+	require.Equal(t, 1, plan.Preempted())
+
+	_, err = allocator.CommitPreemption(plan)
+	require.Error(t, err)
+	allocator.Free(plan.Victims[0].Devices...)
+	_, err = allocator.CommitPreemption(plan)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, indicesOf(allocator.GroupDevices("large")))
+}
+
+func TestPlanPreemptionDefaultPriority(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+	require.NoError(t, allocator.AllocateSpecificFor("legacy", devices...))
+
+	// Allocations made without a request have the default priority.
+	_, err := allocator.PlanPreemption(Request{Owner: "training", Priority: DefaultPriority, Size: 1})
+	require.Error(t, err)
+
+	plan, err := allocator.PlanPreemption(Request{Owner: "training", Priority: DefaultPriority + 1, Size: 1})
+	require.NoError(t, err)
+	require.Len(t, plan.Victims, 1)
+	require.Equal(t, "legacy", plan.Victims[0].Owner)
+	require.Equal(t, DefaultPriority, plan.Victims[0].Priority)
+}
+
+func TestPlanPreemptionWithoutVictims(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+	_, err := allocator.AllocateRequest(Request{Owner: "batch", Size: 2})
+	require.NoError(t, err)
+
+	plan, err := allocator.PlanPreemption(Request{Owner: "training", Priority: 5, Size: 4})
+	require.NoError(t, err)
+	require.Empty(t, plan.Victims)
+
+	allocated, err := allocator.CommitPreemption(plan)
+	require.NoError(t, err)
+	require.Len(t, allocated, 4)
+}
+
+func TestPlanPreemptionErrors(t *testing.T) {
+	allocator := newBusyDGX1VoltaAllocator(t)
+	devices := allocator.GPUs
+
+	testCases := []struct {
+		description string
+		request     Request
+	}{
+		{"invalid size", Request{Owner: "training", Priority: 5}},
+		{"no lower priority", Request{Owner: "training", Priority: 0, Size: 1}},
+		{"too few preemptible GPUs", Request{Owner: "training", Priority: 5, Size: 5}},
+		{"required GPU of a higher priority", Request{Owner: "training", Priority: 5, Size: 1, Required: devices[4:5]}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := allocator.PlanPreemption(tc.request)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestCommitPreemption(t *testing.T) {
+	allocator := newBusyDGX1VoltaAllocator(t)
+	request := Request{Owner: "training", Group: "jobs", Priority: 5, Size: 2, Required: allocator.GPUs[1:2]}
+
+	plan, err := allocator.PlanPreemption(request)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, indicesOf(plan.Devices))
+	require.Equal(t, "batch-b", plan.Victims[0].Owner)
+
+	// The plan is only committed once the victims have been freed.
+	_, err = allocator.CommitPreemption(plan)
+	require.Error(t, err)
+
+	for _, victim := range plan.Victims {
+		allocator.Free(victim.Devices...)
+	}
+	allocated, err := allocator.CommitPreemption(plan)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, indicesOf(allocated))
+
+	owner, ok := allocator.Owner(allocator.GPUs[1])
+	require.True(t, ok)
+	require.Equal(t, "training", owner)
+	require.Equal(t, []int{1, 2}, indicesOf(allocator.GroupDevices("jobs")))
+
+	// The preempting job is itself protected from jobs of equal priority.
+	_, err = allocator.PlanPreemption(Request{Owner: "other", Priority: 5, Size: 2, Required: allocator.GPUs[1:2]})
+	require.Error(t, err)
+
+	// A plan cannot be committed twice.
+	_, err = allocator.CommitPreemption(plan)
+	require.Error(t, err)
+}
@@ -0,0 +1,122 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import "fmt"
+
+// RescanResult describes how the set of GPUs changed during a Rescan.
+type RescanResult struct {
+	// Added holds devices that were not known before the rescan.
+	Added []*Device
+	// Removed holds the previous instances of devices that are no longer present.
+	Removed []*Device
+	// Reindexed holds surviving devices whose index has changed.
+	Reindexed []ReindexedDevice
+	// LostAllocations maps the UUID of each removed device that was still
+	// allocated to the owner that held it.
+	LostAllocations map[string]string
+}
+
+// ReindexedDevice records the previous index of a device whose index changed.
+type ReindexedDevice struct {
+	Device   *Device
+	OldIndex int
+}
+
+// Rescan rediscovers the GPUs on the node and reconciles them with the
+// current allocation state. Devices are matched by UUID since indices may
+// shift after a GPU reset, driver reload or device removal. Allocations and
+// cordons on surviving devices are preserved, while devices that are no
+// longer present are dropped from the allocator.
+func (a *Allocator) Rescan() (*RescanResult, error) {
+	if a.discover == nil {
+		return nil, fmt.Errorf("device discovery is not configured for this allocator")
+	}
+
+	devices, err := a.discover()
+	if err != nil {
+		return nil, fmt.Errorf("error enumerating GPU devices: %v", err)
+	}
+
+	return a.reconcile(devices), nil
+}
+
+// reconcile replaces the devices managed by the allocator with the supplied
+// list, carrying over state for devices with matching UUIDs.
+func (a *Allocator) reconcile(devices DeviceList) *RescanResult {
+	result := &RescanResult{
+		LostAllocations: make(map[string]string),
+	}
+
+	previous := NewDeviceSet(a.GPUs...)
+	remaining := NewDeviceSet()
+	allocated := NewDeviceSet()
+	owners := make(map[string]string)
+	groups := make(map[string]string)
+	priorities := make(map[string]int)
+	cordoned := make(map[string]bool)
+
+	for _, device := range devices {
+		old, ok := previous[device.UUID]
+		if !ok {
+			result.Added = append(result.Added, device)
+			remaining.Insert(device)
+			continue
+		}
+
+		if old.Index != device.Index {
+			result.Reindexed = append(result.Reindexed, ReindexedDevice{device, old.Index})
+		}
+		if a.cordoned[old.UUID] {
+			cordoned[device.UUID] = true
+		}
+
+		if a.allocated.Contains(old) {
+			allocated.Insert(device)
+			owners[device.UUID] = a.owners[old.UUID]
+			if group, ok := a.groups[old.UUID]; ok {
+				groups[device.UUID] = group
+			}
+			if priority, ok := a.priorities[old.UUID]; ok {
+				priorities[device.UUID] = priority
+			}
+		} else {
+			remaining.Insert(device)
+		}
+	}
+
+	current := NewDeviceSet(devices...)
+	for _, old := range previous.SortedSlice() {
+		if current.Contains(old) {
+			continue
+		}
+		result.Removed = append(result.Removed, old)
+		if owner, ok := a.Owner(old); ok {
+			result.LostAllocations[old.UUID] = owner
+		}
+	}
+
+	a.GPUs = devices
+	a.remaining = remaining
+	a.allocated = allocated
+	a.owners = owners
+	a.groups = groups
+	a.priorities = priorities
+	a.cordoned = cordoned
+
+	return result
+}
@@ -0,0 +1,96 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRescan(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewBestEffortPolicy())
+
+	require.NoError(t, allocator.AllocateSpecificFor("job-a", devices[0], devices[3]))
+	require.NoError(t, allocator.AllocateSpecificFor("job-b", devices[4]))
+	require.NoError(t, allocator.Cordon(devices[5]))
+	allocator.recordRequest(Request{Group: "group-a", Priority: 10}, devices[0], devices[3])
+
+	// After the rescan GPU-3 and GPU-5 have moved to indices 1 and 3, GPU-9 is
+	// new and all other GPUs have disappeared.
+	rescanned := New4xRTX8000Node().Devices()
+	for i, uuid := range []int{0, 3, 9, 5} {
+		rescanned[i].UUID = fmt.Sprintf("GPU-%d", uuid)
+	}
+	allocator.discover = func() (DeviceList, error) {
+		return rescanned, nil
+	}
+
+	result, err := allocator.Rescan()
+	require.NoError(t, err)
+
+	require.Equal(t, []*Device{rescanned[2]}, result.Added)
+	require.Equal(t, []int{1, 2, 4, 6, 7}, indicesOf(result.Removed))
+	require.Equal(t, []ReindexedDevice{{rescanned[1], 3}, {rescanned[3], 5}}, result.Reindexed)
+	require.Equal(t, map[string]string{"GPU-4": "job-b"}, result.LostAllocations)
+
+	require.Equal(t, DeviceList(rescanned), DeviceList(allocator.GPUs))
+	for _, d := range rescanned[:2] {
+		owner, allocated := allocator.Owner(d)
+		require.True(t, allocated)
+		require.Equal(t, "job-a", owner)
+	}
+	require.Equal(t, []int{0, 1}, indicesOf(allocator.GroupDevices("group-a")))
+	require.Equal(t, map[string]int{"GPU-0": 10, "GPU-3": 10}, allocator.priorities)
+	require.True(t, allocator.Cordoned(rescanned[3]))
+
+	// Devices obtained before the rescan can still be freed by UUID.
+	allocator.Free(devices[3])
+	require.Equal(t, []int{1, 2}, indicesOf(allocator.available()))
+	require.Equal(t, []int{1, 2}, indicesOf(allocator.Allocate(2)))
+}
+
+func TestRescanFreeRemovedDevice(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewSimplePolicy())
+	require.NoError(t, allocator.AllocateSpecificFor("job-a", devices[4]))
+
+	// GPU-4 disappears in the rescan while still allocated.
+	rescanned := New4xRTX8000Node().Devices()
+	allocator.discover = func() (DeviceList, error) {
+		return rescanned, nil
+	}
+	result, err := allocator.Rescan()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"GPU-4": "job-a"}, result.LostAllocations)
+
+	// Freeing the stale handle, or a device that was never managed, must not
+	// make either of them available.
+	allocator.Free(devices[4], (*Device)(NewTestGPU(9)))
+	require.Equal(t, []int{0, 1, 2, 3}, indicesOf(allocator.available()))
+	require.Equal(t, []int{0, 1, 2, 3}, indicesOf(allocator.Allocate(4)))
+	require.Empty(t, allocator.Allocate(1))
+}
+
+func TestRescanWithoutDiscovery(t *testing.T) {
+	allocator := newAllocatorFrom(NewDGX1VoltaNode().Devices(), NewSimplePolicy())
+
+	_, err := allocator.Rescan()
+	require.Error(t, err)
+}
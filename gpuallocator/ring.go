@@ -0,0 +1,177 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+// maxRingSize is the largest number of GPUs for which the best ring is
+// searched exhaustively.
+const maxRingSize = 16
+
+// pairScoreMatrix returns the pair scores between all GPUs in a set.
+func pairScoreMatrix(gpus []*Device, pairScore PairScoreFunc) [][]int {
+	scores := make([][]int, len(gpus))
+	for i := range gpus {
+		scores[i] = make([]int, len(gpus))
+	}
+	for i := range gpus {
+		for j := i + 1; j < len(gpus); j++ {
+			scores[i][j] = pairScore(gpus[i], gpus[j])
+			scores[j][i] = scores[i][j]
+		}
+	}
+	return scores
+}
+
+// bestRing finds the ring through all GPUs in the set whose slowest link has
+// the highest score. It returns the GPUs in ring order, starting with the
+// first GPU of the set, together with the score of the slowest link.
+//
+// The search is a dynamic program over subsets of the GPUs, where the best
+// bottleneck of a path from the first GPU through a subset ending at a
+// specific GPU is built from the best paths through smaller subsets. Sets
+// with more than maxRingSize GPUs are returned in their original order.
+func bestRing(gpus []*Device, pairScore PairScoreFunc) ([]*Device, int) {
+	n := len(gpus)
+	switch {
+	case n == 0:
+		return nil, 0
+	case n == 1:
+		return []*Device{gpus[0]}, 0
+	case n == 2:
+		return []*Device{gpus[0], gpus[1]}, pairScore(gpus[0], gpus[1])
+	case n > maxRingSize:
+		return append([]*Device{}, gpus...), ringBottleneck(gpus, pairScore)
+	}
+
+	scores := pairScoreMatrix(gpus, pairScore)
+
+	// best[mask][v] holds the best bottleneck of a path starting at GPU 0,
+	// visiting exactly the GPUs in 'mask' and ending at GPU 'v'. A value of
+	// -1 marks unreachable states, and prev[mask][v] holds the GPU visited
+	// before 'v' on that path.
+	const unreachable = -1
+	const unbounded = int(^uint(0) >> 1)
+	full := 1<<uint(n) - 1
+	best := make([][]int, full+1)
+	prev := make([][]int, full+1)
+	for mask := range best {
+		best[mask] = make([]int, n)
+		prev[mask] = make([]int, n)
+		for v := range best[mask] {
+			best[mask][v] = unreachable
+		}
+	}
+	best[1][0] = unbounded
+
+	for mask := 1; mask <= full; mask += 2 {
+		for v := 0; v < n; v++ {
+			if best[mask][v] == unreachable {
+				continue
+			}
+			for w := 1; w < n; w++ {
+				if mask&(1<<uint(w)) != 0 {
+					continue
+				}
+				bottleneck := best[mask][v]
+				if scores[v][w] < bottleneck {
+					bottleneck = scores[v][w]
+				}
+				next := mask | 1<<uint(w)
+				if bottleneck > best[next][w] {
+					best[next][w] = bottleneck
+					prev[next][w] = v
+				}
+			}
+		}
+	}
+
+	last := -1
+	bottleneck := unreachable
+	for v := 1; v < n; v++ {
+		b := best[full][v]
+		if scores[v][0] < b {
+			b = scores[v][0]
+		}
+		if b > bottleneck {
+			bottleneck = b
+			last = v
+		}
+	}
+
+	order := make([]*Device, n)
+	mask := full
+	for i := n - 1; i > 0; i-- {
+		order[i] = gpus[last]
+		last, mask = prev[mask][last], mask&^(1<<uint(last))
+	}
+	order[0] = gpus[0]
+
+	return order, bottleneck
+}
+
+// ringBottleneck returns the score of the slowest link of the ring through
+// the GPUs in the order given.
+func ringBottleneck(ring []*Device, pairScore PairScoreFunc) int {
+	if len(ring) < 2 {
+		return 0
+	}
+	bottleneck := pairScore(ring[len(ring)-1], ring[0])
+	for i := 1; i < len(ring); i++ {
+		if s := pairScore(ring[i-1], ring[i]); s < bottleneck {
+			bottleneck = s
+		}
+	}
+	return bottleneck
+}
+
+// spanningTreeBottleneck returns the score of the slowest link of the
+// spanning tree through the GPUs whose slowest link has the highest score.
+// This is the slowest link of a maximum spanning tree, which is built using
+// Prim's algorithm.
+func spanningTreeBottleneck(gpus []*Device, pairScore PairScoreFunc) int {
+	n := len(gpus)
+	if n < 2 {
+		return 0
+	}
+
+	scores := pairScoreMatrix(gpus, pairScore)
+
+	inTree := make([]bool, n)
+	link := make([]int, n)
+	copy(link, scores[0])
+	inTree[0] = true
+
+	bottleneck := -1
+	for added := 1; added < n; added++ {
+		next := -1
+		for v := 0; v < n; v++ {
+			if !inTree[v] && (next == -1 || link[v] > link[next]) {
+				next = v
+			}
+		}
+		if bottleneck == -1 || link[next] < bottleneck {
+			bottleneck = link[next]
+		}
+		inTree[next] = true
+		for v := 0; v < n; v++ {
+			if !inTree[v] && scores[next][v] > link[v] {
+				link[v] = scores[next][v]
+			}
+		}
+	}
+
+	return bottleneck
+}
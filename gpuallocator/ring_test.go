@@ -0,0 +1,160 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// scoreTable is a pair score function backed by a table of scores between
+// GPU indices. Pairs missing from the table score 0.
+type scoreTable map[[2]int]int
+
+func (s scoreTable) pairScore(gpu0, gpu1 *Device) int {
+	if score, ok := s[[2]int{gpu0.Index, gpu1.Index}]; ok {
+		return score
+	}
+	return s[[2]int{gpu1.Index, gpu0.Index}]
+}
+
+func newTestDevices(n int) []*Device {
+	var node TestNode
+	for i := 0; i < n; i++ {
+		node = append(node, NewTestGPU(i))
+	}
+	return node.Devices()
+}
+
+// square has fast links around the ring 0-1-2-3 and slow diagonals.
+var square = scoreTable{
+	{0, 1}: 10, {1, 2}: 10, {2, 3}: 10, {3, 0}: 10,
+	{0, 2}: 1, {1, 3}: 1,
+}
+
+// star has fast links from GPU 0 to all other GPUs and slow links otherwise.
+var star = scoreTable{
+	{0, 1}: 10, {0, 2}: 10, {0, 3}: 10,
+	{1, 2}: 1, {1, 3}: 1, {2, 3}: 1,
+}
+
+func TestBestRing(t *testing.T) {
+	devices := newTestDevices(4)
+
+	testCases := []struct {
+		description string
+		gpus        []*Device
+		scores      scoreTable
+		bottleneck  int
+	}{
+		{"empty set", nil, square, 0},
+		{"single GPU", devices[:1], square, 0},
+		{"two GPUs", devices[:2], square, 10},
+		{"ring avoids slow diagonals", []*Device{devices[0], devices[2], devices[1], devices[3]}, square, 10},
+		{"star has no fast ring", devices, star, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			ring, bottleneck := bestRing(tc.gpus, tc.scores.pairScore)
+			require.Equal(t, tc.bottleneck, bottleneck)
+			require.ElementsMatch(t, tc.gpus, ring)
+			if len(tc.gpus) > 0 {
+				require.Equal(t, tc.gpus[0], ring[0])
+			}
+			require.Equal(t, tc.bottleneck, ringBottleneck(ring, tc.scores.pairScore))
+		})
+	}
+}
+
+func TestSetObjectives(t *testing.T) {
+	devices := newTestDevices(4)
+	padded := append([]*Device{nil}, devices...)
+
+	testCases := []struct {
+		description string
+		scores      scoreTable
+		objective   SetObjective
+		expected    int
+	}{
+		{"square sum", square, SetObjectiveSum, 42},
+		{"square min pair", square, SetObjectiveMinPair, 1},
+		{"square ring", square, SetObjectiveRing, 10},
+		{"square tree", square, SetObjectiveTree, 10},
+		{"star sum", star, SetObjectiveSum, 33},
+		{"star min pair", star, SetObjectiveMinPair, 1},
+		{"star ring", star, SetObjectiveRing, 1},
+		{"star tree", star, SetObjectiveTree, 10},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			policy := NewBestEffortPolicy(
+				WithPairScore(tc.scores.pairScore),
+				WithSetObjective(tc.objective),
+			).(*bestEffortPolicy)
+			require.Equal(t, tc.expected, policy.setScore(devices))
+			require.Equal(t, tc.expected, policy.setScore(padded))
+		})
+	}
+}
+
+func TestBestEffortRingObjective(t *testing.T) {
+	devices := newTestDevices(4)
+	// The set {0, 1, 3} has the highest total score, but its ring is limited
+	// by the slow link between GPUs 1 and 3.
+	scores := scoreTable{
+		{0, 1}: 20, {0, 2}: 20, {1, 2}: 20,
+		{0, 3}: 50, {1, 3}: 5, {2, 3}: 4,
+	}
+
+	tests := []PolicyAllocTest{
+		{
+			"Sum prefers the set with the highest total score",
+			devices,
+			[]int{0, 1, 2, 3},
+			[]int{},
+			3,
+			[]int{0, 1, 3},
+		},
+	}
+	RunPolicyAllocTests(t, NewBestEffortPolicy(WithPairScore(scores.pairScore)), tests)
+
+	tests = []PolicyAllocTest{
+		{
+			"Ring prefers the set with the fastest ring",
+			devices,
+			[]int{0, 1, 2, 3},
+			[]int{},
+			3,
+			[]int{0, 1, 2},
+		},
+		{
+			"Ring honours required GPUs",
+			devices,
+			[]int{0, 1, 2, 3},
+			[]int{3},
+			3,
+			[]int{0, 1, 3},
+		},
+	}
+	RunPolicyAllocTests(t, NewBestEffortPolicy(
+		WithPairScore(scores.pairScore),
+		WithSetObjective(SetObjectiveRing),
+	), tests)
+}
@@ -0,0 +1,155 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testTraceConfig = TraceConfig{
+	Jobs:             200,
+	Sizes:            []int{1, 1, 2, 4, 8},
+	MeanInterarrival: 1,
+	MeanDuration:     6,
+}
+
+func TestGenerateTraceIsDeterministic(t *testing.T) {
+	trace := GenerateTrace(42, testTraceConfig)
+	require.Len(t, trace, testTraceConfig.Jobs)
+	require.Equal(t, trace, GenerateTrace(42, testTraceConfig))
+	require.NotEqual(t, trace, GenerateTrace(43, testTraceConfig))
+
+	for i, job := range trace {
+		require.Contains(t, testTraceConfig.Sizes, job.Size)
+		require.GreaterOrEqual(t, job.Duration, 1)
+		if i > 0 {
+			require.GreaterOrEqual(t, job.Arrival, trace[i-1].Arrival)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	trace := Trace{
+		{ID: "a", Arrival: 0, Duration: 10, Size: 4},
+		{ID: "b", Arrival: 0, Duration: 10, Size: 2},
+		{ID: "c", Arrival: 1, Duration: 10, Size: 4},
+		{ID: "d", Arrival: 2, Duration: 10, Size: 1, Required: []int{0}},
+		{ID: "e", Arrival: 10, Duration: 10, Size: 8},
+		{ID: "f", Arrival: 20, Duration: 10, Size: 1, Required: []int{9}},
+	}
+
+	results := Simulate(devices, map[string]Policy{
+		"simple":     NewSimplePolicy(),
+		"besteffort": NewBestEffortPolicy(),
+	}, trace)
+	require.Len(t, results, 2)
+	require.Equal(t, "besteffort", results[0].Policy)
+	require.Equal(t, "simple", results[1].Policy)
+
+	for _, result := range results {
+		t.Run(result.Policy, func(t *testing.T) {
+			// Job c does not fit next to a and b, d requires a GPU held by a,
+			// e starts once the other jobs have ended, and f requires a GPU
+			// that does not exist.
+			require.Equal(t, 6, result.Jobs)
+			require.Equal(t, 3, result.Failed)
+			require.Equal(t, 0.5, result.FailureRate)
+			require.Equal(t, []int{0, 1, 2, 10, 20}, sampleTimes(result.Samples))
+			require.Equal(t, 2, result.Samples[0].Free)
+			require.Equal(t, 0, result.Samples[3].Free)
+			require.Greater(t, result.AverageScore, 0.0)
+		})
+	}
+}
+
+func TestSimulateMinScore(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	best := bestSetScores(devices)
+	trace := Trace{
+		{ID: "a", Arrival: 0, Duration: 10, Size: 1, Required: []int{0}},
+		{ID: "b", Arrival: 0, Duration: 10, Size: 1, Required: []int{4}},
+		{ID: "c", Arrival: 1, Duration: 10, Size: 4, MinScore: best[4]},
+		{ID: "d", Arrival: 1, Duration: 10, Size: 4},
+	}
+
+	// With a GPU taken from each NVLink quad, no set of 4 GPUs reaches the
+	// best score, so only the job without a minimum score runs.
+	result := Simulate(devices, map[string]Policy{"besteffort": NewBestEffortPolicy()}, trace)[0]
+	require.Equal(t, 1, result.Failed)
+	require.Equal(t, 2, result.Samples[1].Free)
+
+	generated := GenerateTrace(1, TraceConfig{Jobs: 20, Sizes: []int{1, 4}, MinScores: map[int]int{4: best[4]}})
+	for _, job := range generated {
+		require.Equal(t, map[int]int{1: 0, 4: best[4]}[job.Size], job.MinScore)
+	}
+}
+
+func TestSimulateIsDeterministic(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	policies := map[string]Policy{"besteffort": NewBestEffortPolicy()}
+	trace := GenerateTrace(7, testTraceConfig)
+
+	first := Simulate(devices, policies, trace)[0]
+	second := Simulate(devices, policies, trace)[0]
+	first.AllocateTime, second.AllocateTime = 0, 0
+	require.Equal(t, first, second)
+	require.Greater(t, first.Failed, 0)
+	require.Less(t, first.Failed, first.Jobs)
+}
+
+func TestSimulationResultsOutput(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	results := Simulate(devices, map[string]Policy{
+		"simple": NewSimplePolicy(),
+	}, Trace{{ID: "a", Arrival: 0, Duration: 1, Size: 2}})
+
+	var csv bytes.Buffer
+	require.NoError(t, results.WriteCSV(&csv))
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Equal(t, "policy,jobs,failed,failure_rate,average_score,average_fragmentation,allocate_time_ns", lines[0])
+	require.True(t, strings.HasPrefix(lines[1], "simple,1,0,0.0000,"))
+
+	var out bytes.Buffer
+	require.NoError(t, results.WriteJSON(&out))
+	var decoded SimulationResults
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(t, results, decoded)
+}
+
+func TestReadTrace(t *testing.T) {
+	trace, err := ReadTrace(strings.NewReader(`[{"id": "a", "arrival": 3, "duration": 2, "size": 2, "required": [1]}]`))
+	require.NoError(t, err)
+	require.Equal(t, Trace{{ID: "a", Arrival: 3, Duration: 2, Size: 2, Required: []int{1}}}, trace)
+
+	_, err = ReadTrace(strings.NewReader(`{`))
+	require.Error(t, err)
+}
+
+func sampleTimes(samples []SimulationSample) []int {
+	var times []int
+	for _, sample := range samples {
+		times = append(times, sample.Time)
+	}
+	return times
+}
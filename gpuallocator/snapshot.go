@@ -0,0 +1,170 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// Snapshot is the serialized form of a DeviceList. It records the properties
+// and links of each device, so that a topology can be saved and used as a
+// baseline or replayed on another system. The PCIe hierarchy is not recorded.
+type Snapshot struct {
+	Devices []SnapshotDevice `json:"devices"`
+}
+
+// SnapshotDevice is the serialized form of a Device.
+type SnapshotDevice struct {
+	Index          int            `json:"index"`
+	UUID           string         `json:"uuid"`
+	BusID          string         `json:"busID,omitempty"`
+	Model          string         `json:"model,omitempty"`
+	NUMANode       *uint          `json:"numaNode,omitempty"`
+	CPUs           string         `json:"cpus,omitempty"`
+	NVLinkVersion  int            `json:"nvlinkVersion,omitempty"`
+	PCIeGeneration int            `json:"pcieGeneration,omitempty"`
+	PCIeWidth      int            `json:"pcieWidth,omitempty"`
+	Links          []SnapshotLink `json:"links,omitempty"`
+}
+
+// SnapshotLink is the serialized form of a P2PLink. The type is the name of
+// the link type, such as TwoNVLINKLinks.
+type SnapshotLink struct {
+	Peer int    `json:"peer"`
+	Type string `json:"type"`
+}
+
+// Snapshot returns the serialized form of the devices.
+func (d DeviceList) Snapshot() *Snapshot {
+	snapshot := &Snapshot{
+		Devices: []SnapshotDevice{},
+	}
+	for _, gpu := range d {
+		device := SnapshotDevice{
+			Index:          gpu.Index,
+			UUID:           gpu.UUID,
+			BusID:          gpu.PCI.BusID,
+			Model:          gpu.Model,
+			NUMANode:       gpu.CPUAffinity,
+			NVLinkVersion:  int(gpu.NVLinkVersion),
+			PCIeGeneration: gpu.PCIeGeneration,
+			PCIeWidth:      gpu.PCIeWidth,
+		}
+		if len(gpu.CPUs) != 0 {
+			device.CPUs = gpu.CPUs.String()
+		}
+		for _, peer := range d {
+			for _, link := range sortedLinkTypes(gpu.Links[peer.Index]) {
+				device.Links = append(device.Links, SnapshotLink{Peer: peer.Index, Type: link.String()})
+			}
+		}
+		snapshot.Devices = append(snapshot.Devices, device)
+	}
+	return snapshot
+}
+
+// DeviceList builds a DeviceList from the snapshot. An error is returned if
+// the snapshot is invalid or its link table is inconsistent.
+func (s *Snapshot) DeviceList() (DeviceList, error) {
+	var devices DeviceList
+	byIndex := make(map[int]*Device)
+	for _, sd := range s.Devices {
+		if _, exists := byIndex[sd.Index]; exists {
+			return nil, fmt.Errorf("duplicate device index %d", sd.Index)
+		}
+		gpu := &Device{
+			nvlibDevice: nvlibDevice{
+				UUID:        sd.UUID,
+				PCI:         struct{ BusID string }{BusID: sd.BusID},
+				CPUAffinity: sd.NUMANode,
+			},
+			Index:          sd.Index,
+			Links:          make(map[int][]P2PLink),
+			Model:          sd.Model,
+			NVLinkVersion:  nvml.NvlinkVersion(sd.NVLinkVersion),
+			PCIeGeneration: sd.PCIeGeneration,
+			PCIeWidth:      sd.PCIeWidth,
+		}
+		if sd.CPUs != "" {
+			cpus, err := ParseCPUSet(sd.CPUs)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CPUs for device %d: %v", sd.Index, err)
+			}
+			gpu.CPUs = cpus
+		}
+		devices = append(devices, gpu)
+		byIndex[sd.Index] = gpu
+	}
+
+	for _, sd := range s.Devices {
+		gpu := byIndex[sd.Index]
+		for _, sl := range sd.Links {
+			peer, exists := byIndex[sl.Peer]
+			if !exists {
+				return nil, fmt.Errorf("link from device %d to unknown device %d", sd.Index, sl.Peer)
+			}
+			linkType, err := links.ParseP2PLinkType(sl.Type)
+			if err != nil {
+				return nil, fmt.Errorf("invalid link from device %d to device %d: %v", sd.Index, sl.Peer, err)
+			}
+			gpu.Links[peer.Index] = append(gpu.Links[peer.Index], P2PLink{peer, linkType})
+		}
+	}
+
+	if err := devices.ValidateTopology(); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// WriteSnapshot writes a snapshot of the devices as JSON.
+func (d DeviceList) WriteSnapshot(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(d.Snapshot()); err != nil {
+		return fmt.Errorf("error writing snapshot: %v", err)
+	}
+	return nil
+}
+
+// ReadSnapshot reads a DeviceList from a JSON snapshot.
+func ReadSnapshot(r io.Reader) (DeviceList, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("error reading snapshot: %v", err)
+	}
+	return snapshot.DeviceList()
+}
+
+// LoadSnapshot reads a DeviceList from the JSON snapshot file at 'path'.
+func LoadSnapshot(path string) (DeviceList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening snapshot: %v", err)
+	}
+	defer f.Close()
+
+	return ReadSnapshot(f)
+}
@@ -0,0 +1,127 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	devices := DeviceList(NewDGX1VoltaNode().
+		setNUMANodes(0, 0, 0, 0, 1, 1, 1, 1).
+		withModel("Tesla V100-SXM2-32GB").
+		Devices())
+	for _, d := range devices {
+		d.CPUs = NewCPUSet(0, 1, 2, 3)
+		d.NVLinkVersion = nvml.NVLINK_VERSION_2_0
+		d.PCIeGeneration, d.PCIeWidth = 3, 16
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, devices.WriteSnapshot(&buf))
+
+	restored, err := ReadSnapshot(&buf)
+	require.NoError(t, err)
+	require.Len(t, restored, len(devices))
+	for i, d := range restored {
+		require.Equal(t, devices[i].Index, d.Index)
+		require.Equal(t, devices[i].UUID, d.UUID)
+		require.Equal(t, devices[i].PCI.BusID, d.PCI.BusID)
+		require.Equal(t, devices[i].Model, d.Model)
+		require.Equal(t, *devices[i].CPUAffinity, *d.CPUAffinity)
+		require.Equal(t, devices[i].CPUs, d.CPUs)
+		require.Equal(t, devices[i].NVLinkVersion, d.NVLinkVersion)
+		require.Equal(t, devices[i].PCIeGeneration, d.PCIeGeneration)
+		require.Equal(t, devices[i].PCIeWidth, d.PCIeWidth)
+	}
+	require.True(t, DiffTopology(devices, restored).IsEmpty())
+	require.Equal(t, devices.Fingerprint(), restored.Fingerprint())
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	snapshot := `{
+  "devices": [
+    {"index": 0, "uuid": "GPU-a", "links": [{"peer": 1, "type": "TwoNVLINKLinks"}]},
+    {"index": 1, "uuid": "GPU-b", "numaNode": 1, "links": [{"peer": 0, "type": "TwoNVLINKLinks"}]}
+  ]
+}`
+	require.NoError(t, os.WriteFile(path, []byte(snapshot), 0600))
+
+	devices, err := LoadSnapshot(path)
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+	require.Nil(t, devices[0].CPUAffinity)
+	require.EqualValues(t, 1, *devices[1].CPUAffinity)
+	require.Equal(t, 200, calculateGPUPairScore(devices[0], devices[1]))
+
+	_, err = LoadSnapshot(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorContains(t, err, "error opening snapshot")
+}
+
+func TestReadSnapshotErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		snapshot    string
+		err         string
+	}{
+		{
+			"invalid JSON",
+			`{"devices": [`,
+			"error reading snapshot",
+		},
+		{
+			"duplicate index",
+			`{"devices": [{"index": 0, "uuid": "GPU-a"}, {"index": 0, "uuid": "GPU-b"}]}`,
+			"duplicate device index 0",
+		},
+		{
+			"unknown peer",
+			`{"devices": [{"index": 0, "uuid": "GPU-a", "links": [{"peer": 3, "type": "P2PLinkCrossCPU"}]}]}`,
+			"link from device 0 to unknown device 3",
+		},
+		{
+			"unknown link type",
+			`{"devices": [{"index": 0, "uuid": "GPU-a"}, {"index": 1, "uuid": "GPU-b", "links": [{"peer": 0, "type": "Warp"}]}]}`,
+			`unknown link type: "Warp"`,
+		},
+		{
+			"invalid CPUs",
+			`{"devices": [{"index": 0, "uuid": "GPU-a", "cpus": "3-1"}]}`,
+			"invalid CPUs for device 0",
+		},
+		{
+			"asymmetric links",
+			`{"devices": [{"index": 0, "uuid": "GPU-a"}, {"index": 1, "uuid": "GPU-b", "links": [{"peer": 0, "type": "P2PLinkCrossCPU"}]}]}`,
+			"asymmetric links between GPU 0 and GPU 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := ReadSnapshot(strings.NewReader(tc.snapshot))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+// PeerAwarePolicy is implemented by policies that place an allocation
+// relative to the GPUs already held by its owner group.
+type PeerAwarePolicy interface {
+	Policy
+	// AllocateWithPeers behaves like Allocate, but takes into account the
+	// GPUs in 'peers', which are allocated to the same owner group.
+	AllocateWithPeers(available []*Device, required []*Device, size int, peers []*Device) []*Device
+}
+
+type spreadPolicy struct{}
+
+// NewSpreadPolicy creates a new Spread policy. The Spread policy is the
+// opposite of the BestEffort policy: it places allocations as far apart from
+// one another as the topology allows, so that replicas of a service do not
+// share NUMA nodes, PCIe switches or NVLinks. This limits the impact of a
+// failing switch or a noisy neighbor to as few replicas as possible.
+func NewSpreadPolicy() PeerAwarePolicy {
+	return &spreadPolicy{}
+}
+
+// Allocate GPUs following the Spread policy, without peers.
+func (p *spreadPolicy) Allocate(available []*Device, required []*Device, size int) []*Device {
+	return p.AllocateWithPeers(available, required, size, nil)
+}
+
+// AllocateWithPeers finds the set of 'size' GPUs that is least closely linked
+// to itself and to the 'peers' and returns it. Closeness is measured with the
+// BestEffort pair score: the closeness of a set is the sum of the pair scores
+// within the set and between the set and each peer. Ties are broken in favor
+// of the GPUs with the lowest indices.
+func (p *spreadPolicy) AllocateWithPeers(available []*Device, required []*Device, size int, peers []*Device) []*Device {
+	if size <= 0 {
+		return []*Device{}
+	}
+
+	if len(available) < size {
+		return []*Device{}
+	}
+
+	if len(required) > size {
+		return []*Device{}
+	}
+
+	if !NewDeviceSet(available...).ContainsAll(required) {
+		return []*Device{}
+	}
+
+	// The closeness of each available GPU to the peers does not depend on
+	// the rest of the set, so it is computed once.
+	peerCloseness := make(map[*Device]int)
+	for _, gpu := range available {
+		for _, peer := range peers {
+			peerCloseness[gpu] += calculateGPUPairScore(gpu, peer)
+		}
+	}
+
+	var bestSet []*Device
+	bestCloseness := 0
+	iterateGPUSets(available, size, func(set []*Device) {
+		if !gpuSetContainsAll(set, required) {
+			return
+		}
+		closeness := calculateGPUSetScore(set)
+		for _, gpu := range set {
+			closeness += peerCloseness[gpu]
+		}
+		if bestSet == nil || closeness < bestCloseness {
+			bestSet = append([]*Device{}, set...)
+			bestCloseness = closeness
+		}
+	})
+
+	if bestSet == nil {
+		return []*Device{}
+	}
+	return bestSet
+}
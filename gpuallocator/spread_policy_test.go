@@ -0,0 +1,120 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpreadPolicyAllocate(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+
+	tests := []PolicyAllocTest{
+		{
+			"Single GPU",
+			devices,
+			[]int{0, 1, 2, 3, 4, 5, 6, 7},
+			[]int{},
+			1,
+			[]int{0},
+		},
+		{
+			"Pair across CPUs without NVLinks",
+			devices,
+			[]int{0, 1, 2, 3, 4, 5, 6, 7},
+			[]int{},
+			2,
+			[]int{0, 5},
+		},
+		{
+			"Pair with required GPU",
+			devices,
+			[]int{0, 1, 2, 3, 4, 5, 6, 7},
+			[]int{4},
+			2,
+			[]int{1, 4},
+		},
+		{
+			"Required GPU unavailable",
+			devices,
+			[]int{1, 2, 3},
+			[]int{0},
+			1,
+			[]int{},
+		},
+		{
+			"Not enough GPUs",
+			devices,
+			[]int{0},
+			[]int{},
+			2,
+			[]int{},
+		},
+	}
+
+	RunPolicyAllocTests(t, NewSpreadPolicy(), tests)
+}
+
+func TestSpreadPolicyReplicas(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+	allocator := newAllocatorFrom(devices, NewSpreadPolicy())
+
+	// Each replica lands on a different PCIe switch, alternating between the
+	// CPUs and avoiding NVLinks to the other replicas where possible.
+	var replicas []int
+	for _, owner := range []string{"replica-0", "replica-1", "replica-2", "replica-3"} {
+		allocated, err := allocator.AllocateRequest(Request{Owner: owner, Group: "inference", Size: 1})
+		require.NoError(t, err)
+		replicas = append(replicas, indicesOf(allocated)...)
+	}
+	require.Equal(t, []int{0, 5, 2, 7}, replicas)
+	require.Equal(t, []int{0, 2, 5, 7}, indicesOf(allocator.GroupDevices("inference")))
+
+	// Allocations of other groups are not spread away from the group.
+	allocated, err := allocator.AllocateRequest(Request{Owner: "other", Group: "training", Size: 1})
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, indicesOf(allocated))
+
+	// Freed GPUs leave the group.
+	allocator.Free(devices[5])
+	require.Equal(t, []int{0, 2, 7}, indicesOf(allocator.GroupDevices("inference")))
+}
+
+func TestAllocateRequest(t *testing.T) {
+	devices := NewDGX1VoltaNode().Devices()
+
+	// Policies that are not peer-aware ignore the group.
+	allocator := newAllocatorFrom(devices, NewSimplePolicy())
+	allocated, err := allocator.AllocateRequest(Request{Owner: "a", Group: "g", Size: 2})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1}, indicesOf(allocated))
+	owner, ok := allocator.Owner(devices[0])
+	require.True(t, ok)
+	require.Equal(t, "a", owner)
+	require.Equal(t, []int{0, 1}, indicesOf(allocator.GroupDevices("g")))
+
+	_, err = allocator.AllocateRequest(Request{Owner: "b", Size: 1, Required: []*Device{devices[0]}})
+	require.Error(t, err)
+
+	_, err = allocator.AllocateRequest(Request{Owner: "b", Size: 7})
+	require.Error(t, err)
+
+	_, err = allocator.AllocateRequest(Request{Owner: "b", Size: 0})
+	require.Error(t, err)
+}
@@ -0,0 +1,230 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// topoMatrixLegend is the legend printed by 'nvidia-smi topo -m' for the link
+// types shown in the matrix.
+const topoMatrixLegend = `
+Legend:
+
+  X    = Self
+  SYS  = Connection traversing PCIe as well as the SMP interconnect between NUMA nodes (e.g., QPI/UPI)
+  NODE = Connection traversing PCIe as well as the interconnect between PCIe Host Bridges within a NUMA node
+  PHB  = Connection traversing PCIe as well as a PCIe Host Bridge (typically the CPU)
+  PXB  = Connection traversing multiple PCIe bridges (without traversing the PCIe Host Bridge)
+  PIX  = Connection traversing at most a single PCIe bridge
+  NV#  = Connection traversing a bonded set of # NVLinks
+`
+
+// TopoMatrix renders the links between the devices as a matrix in the format
+// of 'nvidia-smi topo -m', followed by the CPU and NUMA affinity of each GPU.
+func (d DeviceList) TopoMatrix() string {
+	var b strings.Builder
+
+	for _, gpu := range d {
+		fmt.Fprintf(&b, "\tGPU%d", gpu.Index)
+	}
+	b.WriteString("\tCPU Affinity\tNUMA Affinity\n")
+
+	for _, gpu := range d {
+		fmt.Fprintf(&b, "GPU%d", gpu.Index)
+		for _, peer := range d {
+			if peer == gpu {
+				b.WriteString("\t X ")
+				continue
+			}
+			fmt.Fprintf(&b, "\t%s", linkLabel(gpu.Links[peer.Index]))
+		}
+
+		cpus := "N/A"
+		if len(gpu.CPUs) != 0 {
+			cpus = gpu.CPUs.String()
+		}
+		numa := "N/A"
+		if gpu.CPUAffinity != nil {
+			numa = strconv.FormatUint(uint64(*gpu.CPUAffinity), 10)
+		}
+		fmt.Fprintf(&b, "\t%s\t%s\n", cpus, numa)
+	}
+
+	b.WriteString(topoMatrixLegend)
+
+	return b.String()
+}
+
+var gpuColumnRegex = regexp.MustCompile(`^GPU(\d+)$`)
+
+// topoMatrixColumn describes a column of the output of 'nvidia-smi topo -m'.
+type topoMatrixColumn struct {
+	// gpu is the index of the GPU in the column, or -1 for other columns.
+	gpu  int
+	name string
+}
+
+// ParseTopoMatrix builds a DeviceList from the output of 'nvidia-smi topo -m'.
+// This allows topologies captured on other systems to be replayed.
+//
+// Only the information in the matrix is available. Each GPU is given a
+// placeholder UUID of the form GPU-<index>, and GPUs connected by NVLinks only
+// have their NVLinks recorded, as nvidia-smi does not show the PCIe link
+// between them. Pairs shown as N/A have no link. Rows and columns for NICs are
+// ignored.
+func ParseTopoMatrix(r io.Reader) (DeviceList, error) {
+	scanner := bufio.NewScanner(r)
+
+	var columns []topoMatrixColumn
+	for columns == nil && scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		columns = parseTopoMatrixHeader(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading topology matrix: %v", err)
+	}
+
+	var devices DeviceList
+	byIndex := make(map[int]*Device)
+	for _, column := range columns {
+		if column.gpu < 0 {
+			continue
+		}
+		if _, exists := byIndex[column.gpu]; exists {
+			return nil, fmt.Errorf("duplicate column for GPU%d", column.gpu)
+		}
+		gpu := &Device{
+			nvlibDevice: nvlibDevice{UUID: fmt.Sprintf("GPU-%d", column.gpu)},
+			Index:       column.gpu,
+			Links:       make(map[int][]P2PLink),
+		}
+		devices = append(devices, gpu)
+		byIndex[column.gpu] = gpu
+	}
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("no GPU columns found in topology matrix")
+	}
+
+	rows := 0
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "Legend:" {
+			break
+		}
+		match := gpuColumnRegex.FindStringSubmatch(fields[0])
+		if match == nil {
+			continue
+		}
+		index, _ := strconv.Atoi(match[1])
+		gpu, exists := byIndex[index]
+		if !exists {
+			return nil, fmt.Errorf("no column for GPU%d", index)
+		}
+		if err := parseTopoMatrixRow(gpu, fields[1:], columns, byIndex); err != nil {
+			return nil, fmt.Errorf("error parsing row for GPU%d: %v", index, err)
+		}
+		rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading topology matrix: %v", err)
+	}
+	if rows != len(devices) {
+		return nil, fmt.Errorf("expected %d GPU rows in topology matrix, found %d", len(devices), rows)
+	}
+
+	if err := devices.ValidateTopology(); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// parseTopoMatrixHeader returns the columns of the header line of the output
+// of 'nvidia-smi topo -m'. Multi-word column names such as 'CPU Affinity' are
+// joined.
+func parseTopoMatrixHeader(header string) []topoMatrixColumn {
+	columns := []topoMatrixColumn{}
+	fields := strings.Fields(header)
+	for i := 0; i < len(fields); i++ {
+		if match := gpuColumnRegex.FindStringSubmatch(fields[i]); match != nil {
+			index, _ := strconv.Atoi(match[1])
+			columns = append(columns, topoMatrixColumn{gpu: index, name: fields[i]})
+			continue
+		}
+		name := fields[i]
+		for _, multiWord := range []string{"CPU Affinity", "NUMA Affinity", "GPU NUMA ID"} {
+			words := strings.Fields(multiWord)
+			if i+len(words) <= len(fields) && strings.Join(fields[i:i+len(words)], " ") == multiWord {
+				name = multiWord
+				i += len(words) - 1
+				break
+			}
+		}
+		columns = append(columns, topoMatrixColumn{gpu: -1, name: name})
+	}
+	return columns
+}
+
+// parseTopoMatrixRow records the links and affinity of a GPU from the values
+// of its row in the output of 'nvidia-smi topo -m'.
+func parseTopoMatrixRow(gpu *Device, values []string, columns []topoMatrixColumn, byIndex map[int]*Device) error {
+	for i, column := range columns {
+		if i >= len(values) {
+			break
+		}
+		value := values[i]
+		switch {
+		case column.gpu == gpu.Index:
+			if value != "X" {
+				return fmt.Errorf("expected X for GPU%d, got %q", column.gpu, value)
+			}
+		case column.gpu >= 0 && value == "N/A":
+			// TopoMatrix() shows pairs without a recorded link as N/A.
+		case column.gpu >= 0:
+			linkType, err := links.ParseAbbreviation(value)
+			if err != nil {
+				return fmt.Errorf("link to GPU%d: %v", column.gpu, err)
+			}
+			peer := byIndex[column.gpu]
+			gpu.Links[peer.Index] = append(gpu.Links[peer.Index], P2PLink{peer, linkType})
+		case column.name == "CPU Affinity" && value != "N/A":
+			cpus, err := ParseCPUSet(value)
+			if err != nil {
+				return fmt.Errorf("invalid CPU affinity: %v", err)
+			}
+			gpu.CPUs = cpus
+		case column.name == "NUMA Affinity" && value != "N/A":
+			node, err := strconv.ParseUint(value, 10, 0)
+			if err != nil {
+				return fmt.Errorf("invalid NUMA affinity %q: %v", value, err)
+			}
+			numa := uint(node)
+			gpu.CPUAffinity = &numa
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+const capturedTopoMatrix = `	GPU0	GPU1	GPU2	GPU3	NIC0	NIC1	CPU Affinity	NUMA Affinity	GPU NUMA ID
+GPU0	 X 	NV4	PXB	SYS	PIX	SYS	0-23,48-71	0		N/A
+GPU1	NV4	 X 	PXB	SYS	PXB	SYS	0-23,48-71	0		N/A
+GPU2	PXB	PXB	 X 	SOC	PXB	SYS	0-23,48-71	0		N/A
+GPU3	SYS	SYS	SYS	 X 	SYS	PIX	24-47,72-95	1		N/A
+NIC0	PIX	PXB	PXB	SYS	 X 	SYS
+NIC1	SYS	SYS	SYS	PIX	SYS	 X 
+
+Legend:
+
+  X    = Self
+  SYS  = Connection traversing PCIe as well as the SMP interconnect between NUMA nodes (e.g., QPI/UPI)
+  NODE = Connection traversing PCIe as well as the interconnect between PCIe Host Bridges within a NUMA node
+  PHB  = Connection traversing PCIe as well as a PCIe Host Bridge (typically the CPU)
+  PXB  = Connection traversing multiple PCIe bridges (without traversing the PCIe Host Bridge)
+  PIX  = Connection traversing at most a single PCIe bridge
+  NV#  = Connection traversing a bonded set of # NVLinks
+
+NIC Legend:
+
+  NIC0: mlx5_0
+  NIC1: mlx5_1
+`
+
+func TestParseTopoMatrix(t *testing.T) {
+	devices, err := ParseTopoMatrix(strings.NewReader(capturedTopoMatrix))
+	require.NoError(t, err)
+	require.Len(t, devices, 4)
+
+	for i, d := range devices {
+		require.Equal(t, i, d.Index)
+		require.NotNil(t, d.CPUAffinity)
+	}
+	require.Equal(t, "0-23,48-71", devices[0].CPUs.String())
+	require.EqualValues(t, 0, *devices[0].CPUAffinity)
+	require.Equal(t, "24-47,72-95", devices[3].CPUs.String())
+	require.EqualValues(t, 1, *devices[3].CPUAffinity)
+
+	require.Equal(t, []P2PLink{{devices[1], links.FourNVLINKLinks}}, devices[0].Links[1])
+	require.Equal(t, []P2PLink{{devices[2], links.P2PLinkMultiSwitch}}, devices[0].Links[2])
+	require.Equal(t, []P2PLink{{devices[3], links.P2PLinkCrossCPU}}, devices[2].Links[3])
+	require.Len(t, devices[0].Links, 3)
+
+	require.Equal(t, []int{0, 1}, indicesOf(NewBestEffortPolicy().Allocate(devices, nil, 2)))
+}
+
+func TestTopoMatrixRoundTrip(t *testing.T) {
+	devices := DeviceList(NewDGX1VoltaNode().setNUMANodes(0, 0, 0, 0, 1, 1, 1, 1).Devices())
+	for _, d := range devices {
+		d.CPUs = NewCPUSet(int(*d.CPUAffinity)*2, int(*d.CPUAffinity)*2+1)
+	}
+
+	rendered := devices.TopoMatrix()
+	require.True(t, strings.HasPrefix(rendered, "\tGPU0\tGPU1\tGPU2\tGPU3\tGPU4\tGPU5\tGPU6\tGPU7\tCPU Affinity\tNUMA Affinity\n"))
+	require.Contains(t, rendered, "GPU0\t X \tNV1\tNV1\tNV2\tNV2\tSYS\tSYS\tSYS\t0-1\t0\n")
+
+	parsed, err := ParseTopoMatrix(strings.NewReader(rendered))
+	require.NoError(t, err)
+	require.Equal(t, rendered, parsed.TopoMatrix())
+}
+
+func TestTopoMatrixRoundTripMissingLink(t *testing.T) {
+	devices := DeviceList(New4xRTX8000Node().Devices())
+	delete(devices[0].Links, 1)
+	delete(devices[1].Links, 0)
+
+	rendered := devices.TopoMatrix()
+	require.Contains(t, rendered, "GPU0\t X \tN/A\tSYS\tNV2\t")
+
+	parsed, err := ParseTopoMatrix(strings.NewReader(rendered))
+	require.NoError(t, err)
+	require.Empty(t, parsed[0].Links[1])
+	require.Equal(t, rendered, parsed.TopoMatrix())
+
+	reparsed, err := ParseTopoMatrix(strings.NewReader(parsed.TopoMatrix()))
+	require.NoError(t, err)
+	require.Equal(t, rendered, reparsed.TopoMatrix())
+}
+
+func TestTopoMatrixWithoutAffinity(t *testing.T) {
+	devices := DeviceList(New4xRTX8000Node().Devices())
+
+	rendered := devices.TopoMatrix()
+	require.Contains(t, rendered, "GPU0\t X \tNODE\tSYS\tNV2\tN/A\tN/A\n")
+
+	parsed, err := ParseTopoMatrix(strings.NewReader(rendered))
+	require.NoError(t, err)
+	require.Nil(t, parsed[0].CPUAffinity)
+	require.Empty(t, parsed[0].CPUs)
+}
+
+func TestParseTopoMatrixErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		matrix      string
+		err         string
+	}{
+		{
+			"empty input",
+			"",
+			"no GPU columns found",
+		},
+		{
+			"unknown link type",
+			"\tGPU0\tGPU1\nGPU0\t X \tFOO\nGPU1\tFOO\t X \n",
+			`unknown link type: "FOO"`,
+		},
+		{
+			"missing self",
+			"\tGPU0\tGPU1\nGPU0\tSYS\tSYS\nGPU1\tSYS\t X \n",
+			"expected X for GPU0",
+		},
+		{
+			"missing row",
+			"\tGPU0\tGPU1\nGPU0\t X \tSYS\n",
+			"expected 2 GPU rows",
+		},
+		{
+			"asymmetric links",
+			"\tGPU0\tGPU1\nGPU0\t X \tNV2\nGPU1\tNV1\t X \n",
+			"asymmetric links between GPU 0 and GPU 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := ParseTopoMatrix(strings.NewReader(tc.matrix))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
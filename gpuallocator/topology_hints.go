@@ -0,0 +1,182 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// maxNUMANodes is the number of NUMA nodes that fit into a TopologyHint bitmask.
+const maxNUMANodes = 64
+
+// TopologyHint describes a set of NUMA nodes an allocation can be made from,
+// following the semantics of the kubelet Topology Manager.
+type TopologyHint struct {
+	// NUMANodeAffinity is a bitmask where bit 'i' is set if NUMA node 'i' is
+	// part of the hint.
+	NUMANodeAffinity uint64
+	// Preferred is set if the hint spans the minimal number of NUMA nodes that
+	// can satisfy the request and a high-scoring set exists on those nodes.
+	Preferred bool
+}
+
+// NUMANodes returns the NUMA nodes contained in the hint in ascending order.
+func (h TopologyHint) NUMANodes() []int {
+	var nodes []int
+	for mask := h.NUMANodeAffinity; mask != 0; mask &= mask - 1 {
+		nodes = append(nodes, bits.TrailingZeros64(mask))
+	}
+	return nodes
+}
+
+// GetTopologyHints generates the topology hints for allocating 'size' GPUs
+// out of the 'available' subset of 'devices'.
+//
+// As with the kubelet device manager, a hint is generated for every
+// combination of NUMA nodes whose available GPUs can satisfy the request,
+// and the minimal number of NUMA nodes is computed from all devices,
+// independent of their availability. A hint of minimal size is only marked
+// as preferred if its available GPUs contain a set of 'size' GPUs connected
+// by NVLinks.
+//
+// If none of the devices have NUMA information, nil is returned to signal
+// that there is no NUMA preference.
+func GetTopologyHints(devices DeviceList, available []*Device, size int) []TopologyHint {
+	if size <= 0 || len(available) < size {
+		return []TopologyHint{}
+	}
+
+	var nodes []int
+	seen := make(map[int]bool)
+	for _, d := range devices {
+		if d.CPUAffinity == nil || *d.CPUAffinity >= maxNUMANodes {
+			continue
+		}
+		node := int(*d.CPUAffinity)
+		if !seen[node] {
+			seen[node] = true
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	sort.Ints(nodes)
+
+	minAffinitySize := len(nodes)
+	var hints []TopologyHint
+	iterateNUMANodeMasks(nodes, func(mask uint64) {
+		count := bits.OnesCount64(mask)
+		if count < minAffinitySize && len(devicesInMask(devices, mask)) >= size {
+			minAffinitySize = count
+		}
+
+		if len(devicesInMask(available, mask)) < size {
+			return
+		}
+		hints = append(hints, TopologyHint{NUMANodeAffinity: mask})
+	})
+
+	for i := range hints {
+		if bits.OnesCount64(hints[i].NUMANodeAffinity) != minAffinitySize {
+			continue
+		}
+		hints[i].Preferred = hasNVLinkConnectedSet(devicesInMask(available, hints[i].NUMANodeAffinity), size)
+	}
+
+	return hints
+}
+
+// iterateNUMANodeMasks calls the callback for every combination of the
+// specified NUMA nodes, ordered by the number of nodes in the combination
+// in the same way as the kubelet bitmask.IterateBitMasks function.
+func iterateNUMANodeMasks(nodes []int, callback func(uint64)) {
+	var iterate func(start int, size int, mask uint64)
+	iterate = func(start int, size int, mask uint64) {
+		if size == 0 {
+			callback(mask)
+			return
+		}
+		for i := start; i < len(nodes); i++ {
+			iterate(i+1, size-1, mask|uint64(1)<<uint(nodes[i]))
+		}
+	}
+
+	for size := 1; size <= len(nodes); size++ {
+		iterate(0, size, 0)
+	}
+}
+
+// devicesInMask returns the devices attached to one of the NUMA nodes in the mask.
+func devicesInMask(devices []*Device, mask uint64) []*Device {
+	var filtered []*Device
+	for _, d := range devices {
+		if d.CPUAffinity == nil || *d.CPUAffinity >= maxNUMANodes {
+			continue
+		}
+		if mask&(uint64(1)<<*d.CPUAffinity) != 0 {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// hasNVLinkConnectedSet checks whether 'devices' contains a set of 'size'
+// GPUs that are connected to one another through NVLinks.
+func hasNVLinkConnectedSet(devices []*Device, size int) bool {
+	if size == 1 {
+		return len(devices) > 0
+	}
+
+	found := false
+	iterateGPUSets(devices, size, func(set []*Device) {
+		if !found && isNVLinkConnected(set) {
+			found = true
+		}
+	})
+	return found
+}
+
+// isNVLinkConnected checks whether the NVLinks between the GPUs in a set form
+// a connected graph.
+func isNVLinkConnected(set []*Device) bool {
+	visited := map[*Device]bool{set[0]: true}
+	queue := []*Device{set[0]}
+	for len(queue) > 0 {
+		gpu := queue[0]
+		queue = queue[1:]
+		for _, peer := range set {
+			if visited[peer] || !hasNVLink(gpu, peer) {
+				continue
+			}
+			visited[peer] = true
+			queue = append(queue, peer)
+		}
+	}
+	return len(visited) == len(set)
+}
+
+// hasNVLink checks whether two GPUs are directly connected by an NVLink.
+func hasNVLink(gpu0 *Device, gpu1 *Device) bool {
+	for _, link := range gpu0.Links[gpu1.Index] {
+		if link.Type.IsNVLink() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTopologyHints(t *testing.T) {
+	dgx1 := NewDGX1VoltaNode().setNUMANodes(0, 0, 0, 0, 1, 1, 1, 1).Devices()
+	rtx := New4xRTX8000Node().setNUMANodes(0, 0, 1, 1).Devices()
+
+	testCases := []struct {
+		description string
+		devices     []*Device
+		available   []int
+		size        int
+		expected    []TopologyHint
+	}{
+		{
+			description: "single NUMA node hints are preferred",
+			devices:     dgx1,
+			available:   []int{0, 1, 2, 3, 4, 5, 6, 7},
+			size:        4,
+			expected: []TopologyHint{
+				{NUMANodeAffinity: 0b01, Preferred: true},
+				{NUMANodeAffinity: 0b10, Preferred: true},
+				{NUMANodeAffinity: 0b11, Preferred: false},
+			},
+		},
+		{
+			description: "unavailable devices are not counted",
+			devices:     dgx1,
+			available:   []int{0, 4, 5},
+			size:        2,
+			expected: []TopologyHint{
+				{NUMANodeAffinity: 0b10, Preferred: true},
+				{NUMANodeAffinity: 0b11, Preferred: false},
+			},
+		},
+		{
+			description: "minimal size is computed from all devices",
+			devices:     dgx1,
+			available:   []int{0, 1, 4, 5},
+			size:        3,
+			expected: []TopologyHint{
+				{NUMANodeAffinity: 0b11, Preferred: false},
+			},
+		},
+		{
+			description: "no NVLink-connected set is not preferred",
+			devices:     rtx,
+			available:   []int{0, 1, 2, 3},
+			size:        2,
+			expected: []TopologyHint{
+				{NUMANodeAffinity: 0b01, Preferred: false},
+				{NUMANodeAffinity: 0b10, Preferred: false},
+				{NUMANodeAffinity: 0b11, Preferred: false},
+			},
+		},
+		{
+			description: "request larger than available",
+			devices:     dgx1,
+			available:   []int{0, 1},
+			size:        4,
+			expected:    []TopologyHint{},
+		},
+		{
+			description: "no NUMA information",
+			devices:     NewDGX1VoltaNode().Devices(),
+			available:   []int{0, 1, 2, 3},
+			size:        2,
+			expected:    nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			available := GetDevicesFromIndices(tc.devices, tc.available)
+			hints := GetTopologyHints(tc.devices, available, tc.size)
+			require.Equal(t, tc.expected, hints)
+		})
+	}
+}
+
+func TestTopologyHintNUMANodes(t *testing.T) {
+	require.Equal(t, []int{0, 3, 63}, TopologyHint{NUMANodeAffinity: 1 | 1<<3 | 1<<63}.NUMANodes())
+	require.Nil(t, TopologyHint{}.NUMANodes())
+}
@@ -0,0 +1,259 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TopologyErrorType describes the kind of inconsistency found in the link
+// table of a list of devices.
+type TopologyErrorType int
+
+// Kinds of inconsistencies found in link tables.
+const (
+	// AsymmetricLinks indicates that the links reported from a GPU to its
+	// peer differ from the links reported from the peer back to the GPU.
+	AsymmetricLinks TopologyErrorType = iota
+	// SelfLinks indicates that a GPU reports links to itself.
+	SelfLinks
+	// OutOfRangeLinks indicates that a GPU reports links to a peer that is
+	// not part of the device list.
+	OutOfRangeLinks
+)
+
+// String returns a description of the TopologyErrorType.
+func (t TopologyErrorType) String() string {
+	switch t {
+	case AsymmetricLinks:
+		return "asymmetric links"
+	case SelfLinks:
+		return "self-referencing links"
+	case OutOfRangeLinks:
+		return "out-of-range links"
+	}
+	return fmt.Sprintf("unknown topology error %d", int(t))
+}
+
+// TopologyError describes a single inconsistency in the link table of a list
+// of devices.
+type TopologyError struct {
+	Type TopologyErrorType
+	// GPU is the index of the GPU reporting the links.
+	GPU int
+	// Peer is the index of the GPU the links point to.
+	Peer int
+	// Links holds the links reported from GPU to Peer.
+	Links []P2PLink
+	// PeerLinks holds the links reported from Peer to GPU. It is only set
+	// for AsymmetricLinks.
+	PeerLinks []P2PLink
+}
+
+// Error returns a description of the TopologyError.
+func (e *TopologyError) Error() string {
+	switch e.Type {
+	case AsymmetricLinks:
+		return fmt.Sprintf("%v between GPU %v and GPU %v: %v vs %v", e.Type, e.GPU, e.Peer, linkTypes(e.Links), linkTypes(e.PeerLinks))
+	default:
+		return fmt.Sprintf("%v from GPU %v to GPU %v: %v", e.Type, e.GPU, e.Peer, linkTypes(e.Links))
+	}
+}
+
+// TopologyErrors holds all inconsistencies found in the link table of a list
+// of devices.
+type TopologyErrors []*TopologyError
+
+// Error returns a description of all TopologyErrors.
+func (e TopologyErrors) Error() string {
+	var errs []string
+	for _, err := range e {
+		errs = append(errs, err.Error())
+	}
+	return fmt.Sprintf("invalid GPU topology: %v", strings.Join(errs, "; "))
+}
+
+// ValidateTopology checks the link table of the devices for links that are
+// not reported in both directions, links from a GPU to itself, and links to
+// GPUs that are not part of the list. All problems found are returned as
+// TopologyErrors. If the link table is consistent, nil is returned.
+func (d DeviceList) ValidateTopology() error {
+	if errs := d.topologyErrors(); len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// RepairTopology makes the link table of the devices consistent. Links from a
+// GPU to itself or to GPUs outside of the list are removed. Where the links
+// between two GPUs differ by direction, the direction with the lower score is
+// used for both. The problems that were repaired are returned.
+func (d DeviceList) RepairTopology() TopologyErrors {
+	errs := d.topologyErrors()
+
+	// Invalid links are removed before asymmetric links are symmetrized, so
+	// that invalid links are not copied to the other direction.
+	byIndex := d.byIndex()
+	for _, err := range errs {
+		if err.Type == SelfLinks || err.Type == OutOfRangeLinks {
+			delete(byIndex[err.GPU].Links, err.Peer)
+		}
+	}
+	for _, err := range errs {
+		if err.Type != AsymmetricLinks {
+			continue
+		}
+		gpu, peer := byIndex[err.GPU], byIndex[err.Peer]
+		if isLesserLinks(err.Links, err.PeerLinks) {
+			setLinks(peer, gpu, err.Links)
+		} else {
+			setLinks(gpu, peer, err.PeerLinks)
+		}
+	}
+
+	return errs
+}
+
+// topologyErrors returns all inconsistencies in the link table of the
+// devices, ordered by GPU and peer index. Asymmetric links are reported once
+// per pair of GPUs.
+func (d DeviceList) topologyErrors() TopologyErrors {
+	byIndex := d.byIndex()
+
+	var errs TopologyErrors
+	for _, gpu := range d {
+		for peerIndex, links := range gpu.Links {
+			peer, exists := byIndex[peerIndex]
+			switch {
+			case peerIndex == gpu.Index:
+				errs = append(errs, &TopologyError{Type: SelfLinks, GPU: gpu.Index, Peer: peerIndex, Links: links})
+			case !exists || !linksPointTo(links, peer):
+				errs = append(errs, &TopologyError{Type: OutOfRangeLinks, GPU: gpu.Index, Peer: peerIndex, Links: links})
+			}
+		}
+	}
+
+	// Links are compared by pair of GPUs, so that each asymmetric pair is
+	// only reported once. Invalid links have been reported above and are
+	// treated as missing.
+	for i, gpu := range d {
+		for _, peer := range d[i+1:] {
+			links := validLinks(gpu, peer)
+			peerLinks := validLinks(peer, gpu)
+			if sameLinkTypes(links, peerLinks) {
+				continue
+			}
+			gpu, peer := gpu, peer
+			if peer.Index < gpu.Index {
+				gpu, peer = peer, gpu
+				links, peerLinks = peerLinks, links
+			}
+			errs = append(errs, &TopologyError{Type: AsymmetricLinks, GPU: gpu.Index, Peer: peer.Index, Links: links, PeerLinks: peerLinks})
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].GPU != errs[j].GPU {
+			return errs[i].GPU < errs[j].GPU
+		}
+		return errs[i].Peer < errs[j].Peer
+	})
+
+	return errs
+}
+
+// byIndex returns the devices in the list keyed by their index.
+func (d DeviceList) byIndex() map[int]*Device {
+	byIndex := make(map[int]*Device)
+	for _, gpu := range d {
+		byIndex[gpu.Index] = gpu
+	}
+	return byIndex
+}
+
+// linksPointTo checks that all links point to the specified GPU.
+func linksPointTo(links []P2PLink, gpu *Device) bool {
+	for _, link := range links {
+		if link.GPU != gpu {
+			return false
+		}
+	}
+	return true
+}
+
+// sameLinkTypes checks whether two sets of links have the same link types,
+// irrespective of their order.
+func sameLinkTypes(links0, links1 []P2PLink) bool {
+	if len(links0) != len(links1) {
+		return false
+	}
+	types0 := linkTypes(links0)
+	types1 := linkTypes(links1)
+	for i := range types0 {
+		if types0[i] != types1[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// linkTypes returns the sorted link types of a set of links.
+func linkTypes(links []P2PLink) []string {
+	types := []string{}
+	for _, link := range links {
+		types = append(types, link.Type.String())
+	}
+	sort.Strings(types)
+	return types
+}
+
+// isLesserLinks checks whether the first set of links scores lower than the
+// second. Ties are broken by the number of links.
+func isLesserLinks(links0, links1 []P2PLink) bool {
+	score0 := calculateLinkScore(links0)
+	score1 := calculateLinkScore(links1)
+	if score0 != score1 {
+		return score0 < score1
+	}
+	return len(links0) <= len(links1)
+}
+
+// validLinks returns the links from one GPU to another, or nil if any of them
+// point to a different GPU.
+func validLinks(from, to *Device) []P2PLink {
+	links := from.Links[to.Index]
+	if from == to || !linksPointTo(links, to) {
+		return nil
+	}
+	return links
+}
+
+// setLinks replaces the links from one GPU to another with links of the same
+// types as those specified.
+func setLinks(from, to *Device, links []P2PLink) {
+	if len(links) == 0 {
+		delete(from.Links, to.Index)
+		return
+	}
+	var replaced []P2PLink
+	for _, link := range links {
+		replaced = append(replaced, P2PLink{GPU: to, Type: link.Type})
+	}
+	from.Links[to.Index] = replaced
+}
@@ -0,0 +1,130 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// newAsymmetricNode creates a node where one of the two NVLinks between GPUs
+// 0 and 1 is only reported by GPU 0.
+func newAsymmetricNode() TestNode {
+	node := TestNode{
+		NewTestGPU(0),
+		NewTestGPU(1),
+		NewTestGPU(2),
+	}
+	node.addBidirectionalLink(0, 1, links.P2PLinkSameCPU)
+	node.AddLink(0, 1, links.TwoNVLINKLinks)
+	node.AddLink(1, 0, links.SingleNVLINKLink)
+	node.addBidirectionalLink(0, 2, links.P2PLinkSameCPU)
+	node.addBidirectionalLink(1, 2, links.P2PLinkSameCPU)
+	return node
+}
+
+func TestValidateTopology(t *testing.T) {
+	require.NoError(t, DeviceList(NewDGX1VoltaNode().Devices()).ValidateTopology())
+	require.NoError(t, DeviceList(New4xRTX8000Node().Devices()).ValidateTopology())
+
+	testCases := []struct {
+		description string
+		node        func() TestNode
+		expected    []TopologyError
+	}{
+		{
+			"asymmetric link types",
+			newAsymmetricNode,
+			[]TopologyError{{Type: AsymmetricLinks, GPU: 0, Peer: 1}},
+		},
+		{
+			"link missing in one direction",
+			func() TestNode {
+				node := TestNode{NewTestGPU(0), NewTestGPU(1)}
+				node.AddLink(1, 0, links.SingleNVLINKLink)
+				return node
+			},
+			[]TopologyError{{Type: AsymmetricLinks, GPU: 0, Peer: 1}},
+		},
+		{
+			"self-referencing link",
+			func() TestNode {
+				node := TestNode{NewTestGPU(0), NewTestGPU(1)}
+				node.addBidirectionalLink(0, 1, links.P2PLinkSameCPU)
+				node.AddLink(1, 1, links.SingleNVLINKLink)
+				return node
+			},
+			[]TopologyError{{Type: SelfLinks, GPU: 1, Peer: 1}},
+		},
+		{
+			"out-of-range link",
+			func() TestNode {
+				node := TestNode{NewTestGPU(0), NewTestGPU(1)}
+				node.addBidirectionalLink(0, 1, links.P2PLinkSameCPU)
+				node[0].AddLink(NewTestGPU(7), links.SingleNVLINKLink)
+				return node
+			},
+			[]TopologyError{{Type: OutOfRangeLinks, GPU: 0, Peer: 7}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			devices := DeviceList(tc.node().Devices())
+
+			err := devices.ValidateTopology()
+			var errs TopologyErrors
+			require.True(t, errors.As(err, &errs))
+			require.Len(t, errs, len(tc.expected))
+			for i := range tc.expected {
+				require.Equal(t, tc.expected[i].Type, errs[i].Type)
+				require.Equal(t, tc.expected[i].GPU, errs[i].GPU)
+				require.Equal(t, tc.expected[i].Peer, errs[i].Peer)
+			}
+
+			require.Equal(t, errs, devices.RepairTopology())
+			require.NoError(t, devices.ValidateTopology())
+		})
+	}
+}
+
+func TestRepairTopologyUsesTheLowerDirection(t *testing.T) {
+	devices := DeviceList(newAsymmetricNode().Devices())
+
+	devices.RepairTopology()
+
+	for _, pair := range [][2]*Device{{devices[0], devices[1]}, {devices[1], devices[0]}} {
+		var types []links.P2PLinkType
+		for _, link := range pair[0].Links[pair[1].Index] {
+			require.Equal(t, pair[1], link.GPU)
+			types = append(types, link.Type)
+		}
+		require.ElementsMatch(t, []links.P2PLinkType{links.P2PLinkSameCPU, links.SingleNVLINKLink}, types)
+	}
+}
+
+func TestCalculateGPUPairScoreAsymmetric(t *testing.T) {
+	devices := newAsymmetricNode().Devices()
+
+	require.NotPanics(t, func() { calculateGPUPairScore(devices[0], devices[1]) })
+	require.Equal(t, 120, calculateGPUPairScore(devices[0], devices[1]))
+	require.Equal(t, 120, calculateGPUPairScore(devices[1], devices[0]))
+}
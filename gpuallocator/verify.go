@@ -0,0 +1,260 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VerificationIssueType describes the kind of degradation found when
+// verifying a topology against an expected baseline.
+type VerificationIssueType int
+
+// Kinds of degradations found when verifying a topology.
+const (
+	// MissingGPU indicates that an expected GPU is not present.
+	MissingGPU VerificationIssueType = iota
+	// MissingLink indicates that two GPUs are no longer linked as expected,
+	// such as when all NVLinks between them are down.
+	MissingLink
+	// DowngradedLink indicates that two GPUs have fewer NVLinks between them
+	// than expected.
+	DowngradedLink
+	// WrongNUMANode indicates that a GPU is not on the expected NUMA node.
+	WrongNUMANode
+)
+
+// String returns a description of the VerificationIssueType.
+func (t VerificationIssueType) String() string {
+	switch t {
+	case MissingGPU:
+		return "missing GPU"
+	case MissingLink:
+		return "missing link"
+	case DowngradedLink:
+		return "downgraded link"
+	case WrongNUMANode:
+		return "wrong NUMA node"
+	}
+	return fmt.Sprintf("unknown issue %d", int(t))
+}
+
+// VerificationIssue describes a single degradation of a topology compared to
+// its expected baseline.
+type VerificationIssue struct {
+	Type VerificationIssueType
+	// GPU0 is the affected GPU. For MissingGPU, only GPU0.Old is set.
+	GPU0 MatchedDevice
+	// GPU1 is the peer of GPU0 for MissingLink and DowngradedLink.
+	GPU1 MatchedDevice
+	// Expected and Actual describe the expected and actual state, such as
+	// the link types or NUMA node.
+	Expected string
+	Actual   string
+}
+
+// String returns a description of the VerificationIssue.
+func (i VerificationIssue) String() string {
+	switch i.Type {
+	case MissingGPU:
+		return fmt.Sprintf("%v: GPU %d (%v)", i.Type, i.GPU0.Old.Index, i.GPU0.Old.UUID)
+	case MissingLink, DowngradedLink:
+		return fmt.Sprintf("%v between %v and %v: expected %v, got %v", i.Type, i.GPU0, i.GPU1, i.Expected, i.Actual)
+	default:
+		return fmt.Sprintf("%v for %v: expected %v, got %v", i.Type, i.GPU0, i.Expected, i.Actual)
+	}
+}
+
+// VerificationReport holds the degradations found when verifying a topology
+// against an expected baseline.
+type VerificationReport struct {
+	Issues []VerificationIssue
+}
+
+// OK returns true if no degradations were found.
+func (r *VerificationReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// DegradedDevices returns the devices of the verified list that have a
+// missing or downgraded link. Devices on the wrong NUMA node have healthy
+// links and are reported by MisplacedDevices instead.
+func (r *VerificationReport) DegradedDevices() DeviceSet {
+	return r.devices(MissingLink, DowngradedLink)
+}
+
+// MisplacedDevices returns the devices of the verified list that are not on
+// the expected NUMA node.
+func (r *VerificationReport) MisplacedDevices() DeviceSet {
+	return r.devices(WrongNUMANode)
+}
+
+// devices returns the devices of the verified list that are affected by an
+// issue of one of the specified types.
+func (r *VerificationReport) devices(types ...VerificationIssueType) DeviceSet {
+	devices := NewDeviceSet()
+	for _, issue := range r.Issues {
+		matches := false
+		for _, t := range types {
+			matches = matches || issue.Type == t
+		}
+		if !matches {
+			continue
+		}
+		if issue.GPU0.New != nil {
+			devices.Insert(issue.GPU0.New)
+		}
+		if issue.GPU1.New != nil {
+			devices.Insert(issue.GPU1.New)
+		}
+	}
+	return devices
+}
+
+// String returns a human-readable report of the degradations.
+func (r *VerificationReport) String() string {
+	if r.OK() {
+		return "Topology matches the expected baseline"
+	}
+	var issues []string
+	for _, issue := range r.Issues {
+		issues = append(issues, issue.String())
+	}
+	return strings.Join(issues, "\n")
+}
+
+// Verify checks the devices against an expected baseline, such as a snapshot
+// of the same node or the topology of a known system. Devices are matched by
+// UUID, then by PCI bus ID and finally by index.
+//
+// Only degradations are reported: missing GPUs, GPU pairs that have lost
+// their links or some of their NVLinks, and GPUs on a different NUMA node.
+// Links or NUMA information missing from the baseline are not checked.
+func (d DeviceList) Verify(expected DeviceList) *VerificationReport {
+	report := &VerificationReport{}
+
+	matched := matchDevices(expected, d)
+	used := make(map[*Device]bool)
+	for _, m := range matched {
+		used[m.New] = true
+	}
+	byIndex := make(map[int]*Device)
+	for _, gpu := range d {
+		if !used[gpu] {
+			byIndex[gpu.Index] = gpu
+		}
+	}
+
+	var pairs []MatchedDevice
+	for _, gpu := range expected {
+		m, ok := matched[gpu]
+		if !ok {
+			actual, found := byIndex[gpu.Index]
+			if !found {
+				report.Issues = append(report.Issues, VerificationIssue{Type: MissingGPU, GPU0: MatchedDevice{Old: gpu}})
+				continue
+			}
+			m = MatchedDevice{gpu, actual}
+			delete(byIndex, gpu.Index)
+		}
+		pairs = append(pairs, m)
+
+		if m.Old.CPUAffinity != nil && !sameNUMANode(m.Old.CPUAffinity, m.New.CPUAffinity) {
+			report.Issues = append(report.Issues, VerificationIssue{
+				Type:     WrongNUMANode,
+				GPU0:     m,
+				Expected: numaNodeString(m.Old.CPUAffinity),
+				Actual:   numaNodeString(m.New.CPUAffinity),
+			})
+		}
+	}
+
+	for i, gpu0 := range pairs {
+		for _, gpu1 := range pairs[i+1:] {
+			expectedLinks := sortedLinkTypes(gpu0.Old.Links[gpu1.Old.Index])
+			actualLinks := sortedLinkTypes(gpu0.New.Links[gpu1.New.Index])
+
+			issue := VerificationIssue{
+				GPU0:     gpu0,
+				GPU1:     gpu1,
+				Expected: linkTypesString(expectedLinks),
+				Actual:   linkTypesString(actualLinks),
+			}
+			expectedNVLinks := nvlinkCount(expectedLinks)
+			actualNVLinks := nvlinkCount(actualLinks)
+			switch {
+			case len(expectedLinks) != 0 && len(actualLinks) == 0,
+				expectedNVLinks != 0 && actualNVLinks == 0:
+				issue.Type = MissingLink
+			case actualNVLinks < expectedNVLinks:
+				issue.Type = DowngradedLink
+			default:
+				continue
+			}
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	return report
+}
+
+// Verify checks the devices against the topology of the known system. The
+// GPUs are expected to be numbered as in the system's topology, and the GPU
+// model of the first device is used.
+func (s *KnownSystem) Verify(d DeviceList) *VerificationReport {
+	model := ""
+	if len(d) != 0 {
+		model = d[0].Model
+	}
+	return d.Verify(s.Topology(model))
+}
+
+// degradationAwarePolicy wraps a policy to steer allocations away from
+// degraded devices.
+type degradationAwarePolicy struct {
+	policy   Policy
+	degraded DeviceSet
+}
+
+// NewDegradationAwarePolicy creates a policy that avoids the devices with
+// missing or downgraded links in the report. The wrapped policy is first run without
+// the degraded devices, and only if it cannot satisfy the request are they
+// considered. Required devices are always included.
+func NewDegradationAwarePolicy(policy Policy, report *VerificationReport) Policy {
+	return &degradationAwarePolicy{
+		policy:   policy,
+		degraded: report.DegradedDevices(),
+	}
+}
+
+// Allocate allocates 'size' GPUs, preferring GPUs without degraded links.
+func (p *degradationAwarePolicy) Allocate(available []*Device, required []*Device, size int) []*Device {
+	requiredSet := NewDeviceSet(required...)
+
+	var healthy []*Device
+	for _, gpu := range available {
+		if !p.degraded.Contains(gpu) || requiredSet.Contains(gpu) {
+			healthy = append(healthy, gpu)
+		}
+	}
+
+	if allocated := p.policy.Allocate(healthy, required, size); len(allocated) != 0 {
+		return allocated
+	}
+	return p.policy.Allocate(available, required, size)
+}
@@ -0,0 +1,195 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package gpuallocator
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/links"
+)
+
+// newDegradedDGX1VoltaNode creates a DGX-1 Volta node where one of the two
+// NVLinks between GPUs 0 and 3 is down, all NVLinks between GPUs 4 and 7 are
+// down, and GPU 6 reports the wrong NUMA node.
+func newDegradedDGX1VoltaNode() TestNode {
+	node := NewDGX1VoltaNode().setNUMANodes(0, 0, 0, 0, 1, 1, 0, 1)
+	for _, pair := range [][2]int{{0, 3}, {3, 0}} {
+		node[pair[0]].Links[pair[1]] = []P2PLink{
+			{(*Device)(node[pair[1]]), links.P2PLinkHostBridge},
+			{(*Device)(node[pair[1]]), links.SingleNVLINKLink},
+		}
+	}
+	for _, pair := range [][2]int{{4, 7}, {7, 4}} {
+		node[pair[0]].Links[pair[1]] = []P2PLink{
+			{(*Device)(node[pair[1]]), links.P2PLinkHostBridge},
+		}
+	}
+	return node
+}
+
+func TestVerify(t *testing.T) {
+	expected := DeviceList(NewDGX1VoltaNode().setNUMANodes(0, 0, 0, 0, 1, 1, 1, 1).Devices())
+
+	report := DeviceList(NewDGX1VoltaNode().setNUMANodes(0, 0, 0, 0, 1, 1, 1, 1).Devices()).Verify(expected)
+	require.True(t, report.OK())
+	require.Equal(t, "Topology matches the expected baseline", report.String())
+
+	actual := DeviceList(newDegradedDGX1VoltaNode().Devices())
+	report = actual.Verify(expected)
+	require.False(t, report.OK())
+
+	expectedReport := `wrong NUMA node for GPU 6: expected 1, got 0
+downgraded link between GPU 0 and GPU 3: expected PHB+NV2, got PHB+NV1
+missing link between GPU 4 and GPU 7: expected PHB+NV2, got PHB`
+	require.Equal(t, expectedReport, report.String())
+
+	degraded := report.DegradedDevices()
+	require.Len(t, degraded, 4)
+	require.True(t, degraded.ContainsAll([]*Device{actual[0], actual[3], actual[4], actual[7]}))
+
+	misplaced := report.MisplacedDevices()
+	require.Len(t, misplaced, 1)
+	require.True(t, misplaced.Contains(actual[6]))
+}
+
+func TestVerifyMissingGPU(t *testing.T) {
+	expected := DeviceList(NewDGX1VoltaNode().Devices())
+	actual := DeviceList(NewDGX1VoltaNode().Devices()[:7])
+
+	report := actual.Verify(expected)
+	require.Len(t, report.Issues, 1)
+	require.Equal(t, MissingGPU, report.Issues[0].Type)
+	require.Equal(t, "missing GPU: GPU 7 (GPU-7)", report.String())
+	require.Empty(t, report.DegradedDevices())
+}
+
+func TestKnownSystemVerify(t *testing.T) {
+	system, found := LookupSystem("DGX-1 (Volta)")
+	require.True(t, found)
+
+	healthy := DeviceList(NewDGX1VoltaNode().withModel("Tesla V100-SXM2-16GB").Devices())
+	require.True(t, system.Verify(healthy).OK())
+
+	degraded := DeviceList(newDegradedDGX1VoltaNode().withModel("Tesla V100-SXM2-16GB").Devices())
+	report := system.Verify(degraded)
+	require.Len(t, report.Issues, 2)
+	require.Equal(t, DowngradedLink, report.Issues[0].Type)
+	require.Equal(t, MissingLink, report.Issues[1].Type)
+}
+
+func TestKnownSystemVerifyNVSwitch(t *testing.T) {
+	system, found := LookupSystem("DGX A100")
+	require.True(t, found)
+
+	mocks := newNVSwitchMockDevices(8, 12, "NVIDIA A100-SXM4-40GB")
+	healthy, err := NewDevices(WithNvmlLib(newMockNVML(mocks...)), WithSysfsRoot(t.TempDir()))
+	require.NoError(t, err)
+
+	report := system.Verify(healthy)
+	require.True(t, report.OK(), report.String())
+	require.Len(t, NewDegradationAwarePolicy(NewBestEffortPolicy(), report).Allocate(healthy, nil, 8), 8)
+
+	// Take down the NVLinks of GPU 3 to the NVSwitches.
+	mocks[3].GetNvLinkStateFunc = func(int) (nvml.EnableState, nvml.Return) {
+		return nvml.FEATURE_DISABLED, nvml.SUCCESS
+	}
+	degraded, err := NewDevices(WithNvmlLib(newMockNVML(mocks...)), WithSysfsRoot(t.TempDir()))
+	require.NoError(t, err)
+
+	report = system.Verify(degraded)
+	require.Len(t, report.Issues, 7)
+	for _, issue := range report.Issues {
+		require.Equal(t, MissingLink, issue.Type)
+	}
+	require.Contains(t, report.DegradedDevices(), degraded[3].UUID)
+}
+
+func TestNewDevicesWithExpectedTopology(t *testing.T) {
+	mocks := newFullyConnectedMockDevices(4)
+	expected, err := NewDevices(WithNvmlLib(newMockNVML(mocks...)), WithSysfsRoot(t.TempDir()))
+	require.NoError(t, err)
+
+	// Take down the NVLink between GPU 0 and GPU 1 on both ends.
+	for _, d := range mocks[:2] {
+		d.GetNvLinkStateFunc = func(link int) (nvml.EnableState, nvml.Return) {
+			if link > 0 && link < 3 {
+				return nvml.FEATURE_ENABLED, nvml.SUCCESS
+			}
+			return nvml.FEATURE_DISABLED, nvml.SUCCESS
+		}
+	}
+
+	var report VerificationReport
+	_, err = NewDevices(
+		WithNvmlLib(newMockNVML(mocks...)),
+		WithSysfsRoot(t.TempDir()),
+		WithExpectedTopology(expected, &report),
+	)
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	require.Equal(t, "missing link between GPU 0 and GPU 1: expected SYS+NV1, got SYS", report.String())
+}
+
+func TestDegradationAwarePolicy(t *testing.T) {
+	expected := DeviceList(NewDGX1VoltaNode().Devices())
+	actual := DeviceList(newDegradedDGX1VoltaNode().Devices())
+	policy := NewDegradationAwarePolicy(NewBestEffortPolicy(), actual.Verify(expected))
+
+	tests := []PolicyAllocTest{
+		{
+			"Avoid degraded GPUs",
+			actual,
+			[]int{0, 1, 2, 3, 4, 5, 6, 7},
+			[]int{},
+			2,
+			[]int{1, 2},
+		},
+		{
+			"Use degraded GPUs if required",
+			actual,
+			[]int{0, 1, 2, 3, 4, 5, 6, 7},
+			[]int{0},
+			2,
+			[]int{0, 1},
+		},
+		{
+			"Fall back to degraded GPUs",
+			actual,
+			[]int{0, 1, 2, 3, 4, 5, 6, 7},
+			[]int{},
+			4,
+			[]int{1, 2, 5, 6},
+		},
+	}
+	RunPolicyAllocTests(t, policy, tests)
+}
+
+func TestDegradationAwarePolicyIgnoresNUMANode(t *testing.T) {
+	expected := DeviceList(NewDGX1VoltaNode().setNUMANodes(0, 0, 0, 0, 1, 1, 1, 1).Devices())
+	actual := DeviceList(NewDGX1VoltaNode().setNUMANodes(0, 0, 0, 0, 1, 1, 0, 1).Devices())
+	report := actual.Verify(expected)
+	require.Len(t, report.Issues, 1)
+	require.Empty(t, report.DegradedDevices())
+
+	policy := NewDegradationAwarePolicy(NewBestEffortPolicy(), report)
+	for size := 1; size <= len(actual); size++ {
+		require.Equal(t, NewBestEffortPolicy().Allocate(actual, nil, size), policy.Allocate(actual, nil, size))
+	}
+}
@@ -112,6 +112,80 @@ func (l P2PLinkType) String() string {
 	}
 }
 
+// Abbreviation returns the abbreviation used for the link type in the output
+// of 'nvidia-smi topo -m'. NVLinks are shown as NV# with the number of links.
+// Links between GPUs on the same board are shown as PIX, as by nvidia-smi.
+func (l P2PLinkType) Abbreviation() string {
+	switch {
+	case l.IsNVLink():
+		return fmt.Sprintf("NV%d", l.NVLinkCount())
+	case l == P2PLinkSameBoard, l == P2PLinkSingleSwitch:
+		return "PIX"
+	case l == P2PLinkMultiSwitch:
+		return "PXB"
+	case l == P2PLinkHostBridge:
+		return "PHB"
+	case l == P2PLinkSameCPU:
+		return "NODE"
+	case l == P2PLinkCrossCPU:
+		return "SYS"
+	}
+	return "N/A"
+}
+
+// NVLinkCount returns the number of NVLinks represented by the link type, or
+// 0 if it is not an NVLink.
+func (l P2PLinkType) NVLinkCount() int {
+	if !l.IsNVLink() {
+		return 0
+	}
+	return int(l-SingleNVLINKLink) + 1
+}
+
+// ParseAbbreviation returns the link type for an abbreviation used in the
+// output of 'nvidia-smi topo -m'. Older versions of nvidia-smi used SOC for
+// links now shown as SYS, which is also accepted.
+func ParseAbbreviation(abbreviation string) (P2PLinkType, error) {
+	switch abbreviation {
+	case "PIX":
+		return P2PLinkSingleSwitch, nil
+	case "PXB":
+		return P2PLinkMultiSwitch, nil
+	case "PHB":
+		return P2PLinkHostBridge, nil
+	case "NODE":
+		return P2PLinkSameCPU, nil
+	case "SYS", "SOC":
+		return P2PLinkCrossCPU, nil
+	}
+
+	var count int
+	if _, err := fmt.Sscanf(abbreviation, "NV%d", &count); err == nil && fmt.Sprintf("NV%d", count) == abbreviation {
+		nvlink := SingleNVLINKLink + P2PLinkType(count-1)
+		if count >= 1 && nvlink.IsNVLink() {
+			return nvlink, nil
+		}
+	}
+
+	return P2PLinkUnknown, fmt.Errorf("unknown link type: %q", abbreviation)
+}
+
+// ParseP2PLinkType returns the link type with the specified name as returned
+// by P2PLinkType.String().
+func ParseP2PLinkType(name string) (P2PLinkType, error) {
+	for l := P2PLinkCrossCPU; l <= EighteenNVLINKLinks; l++ {
+		if l.String() == name {
+			return l, nil
+		}
+	}
+	return P2PLinkUnknown, fmt.Errorf("unknown link type: %q", name)
+}
+
+// IsNVLink returns true if the link type represents one or more NVLinks.
+func (l P2PLinkType) IsNVLink() bool {
+	return l >= SingleNVLINKLink && l <= EighteenNVLINKLinks
+}
+
 // GetP2PLink gets the peer-to-peer connectivity between two devices.
 func GetP2PLink(dev1 device.Device, dev2 device.Device) (P2PLinkType, error) {
 	level, ret := dev1.GetTopologyCommonAncestor(dev2)
@@ -140,20 +214,50 @@ func GetP2PLink(dev1 device.Device, dev2 device.Device) (P2PLinkType, error) {
 
 // GetNVLink gets the number of NVLinks between the specified devices.
 func GetNVLink(dev1 device.Device, dev2 device.Device) (P2PLinkType, error) {
-	pciInfos, err := getAllNvLinkRemotePciInfo(dev1)
+	remotes1, err := GetNVLinkRemotes(dev1)
+	if err != nil {
+		return P2PLinkUnknown, err
+	}
+	remotes2, err := GetNVLinkRemotes(dev2)
 	if err != nil {
-		return P2PLinkUnknown, fmt.Errorf("failed to get nvlink remote pci info: %v", err)
+		return P2PLinkUnknown, err
 	}
 
 	dev2PciInfo, ret := dev2.GetPciInfo()
 	if ret != nvml.SUCCESS {
 		return P2PLinkUnknown, fmt.Errorf("failed to get pci info: %v", ret)
 	}
-	dev2BusID := PciInfo(dev2PciInfo).BusID()
 
+	if nvlink := remotes1.NVLinkTo(PciInfo(dev2PciInfo).BusID()); nvlink != P2PLinkUnknown {
+		return nvlink, nil
+	}
+	return remotes1.NVSwitchLinkTo(remotes2), nil
+}
+
+// NVLinkRemotes holds the remote ends of the active NVLinks of a device.
+type NVLinkRemotes struct {
+	// GPUs holds the PCI info of the devices attached directly by an NVLink.
+	GPUs []PciInfo
+	// NVSwitchLinks is the number of NVLinks attached to an NVSwitch.
+	NVSwitchLinks int
+}
+
+// GetNVLinkRemotes queries the remote ends of the NVLinks of the specified
+// device. The result can be used to determine the NVLinks to any number of
+// peers without querying the device again.
+func GetNVLinkRemotes(dev device.Device) (NVLinkRemotes, error) {
+	remotes, err := getAllNvLinkRemotes(dev)
+	if err != nil {
+		return NVLinkRemotes{}, fmt.Errorf("failed to get nvlink remote pci info: %v", err)
+	}
+	return remotes, nil
+}
+
+// NVLinkTo gets the number of NVLinks to the device with the specified bus ID.
+func (r NVLinkRemotes) NVLinkTo(busID string) P2PLinkType {
 	nvlink := P2PLinkUnknown
-	for _, pciInfo := range pciInfos {
-		if pciInfo.BusID() != dev2BusID {
+	for _, pciInfo := range r.GPUs {
+		if pciInfo.BusID() != busID {
 			continue
 		}
 		switch nvlink {
@@ -195,34 +299,78 @@ func GetNVLink(dev1 device.Device, dev2 device.Device) (P2PLinkType, error) {
 			nvlink = EighteenNVLINKLinks
 		}
 	}
-	// TODO(klueska): Handle NVSwitch semantics
 
-	return nvlink, nil
+	return nvlink
 }
 
-// getAllNvLinkRemotePciInfo returns the PCI info for all devices attached to the specified device by an NVLink
-func getAllNvLinkRemotePciInfo(dev device.Device) ([]PciInfo, error) {
-	var pciInfos []PciInfo
+// NVSwitchLinkTo gets the NVLinks to a peer through the NVSwitch fabric. All
+// GPUs attached to NVSwitches are assumed to share a single fabric, and are
+// connected by the smaller number of NVSwitch links of the two, as shown by
+// 'nvidia-smi topo -m'.
+func (r NVLinkRemotes) NVSwitchLinkTo(peer NVLinkRemotes) P2PLinkType {
+	count := r.NVSwitchLinks
+	if peer.NVSwitchLinks < count {
+		count = peer.NVSwitchLinks
+	}
+	if count == 0 {
+		return P2PLinkUnknown
+	}
+	if count > EighteenNVLINKLinks.NVLinkCount() {
+		return EighteenNVLINKLinks
+	}
+	return SingleNVLINKLink + P2PLinkType(count-1)
+}
+
+// GetNVLinkVersion returns the NVLink version of the first active NVLink of
+// the specified device as an nvml.NvlinkVersion value. If the device has no
+// active NVLinks or the version cannot be queried, NVLINK_VERSION_INVALID is
+// returned.
+func GetNVLinkVersion(dev device.Device) nvml.NvlinkVersion {
+	for i := 0; i < nvml.NVLINK_MAX_LINKS; i++ {
+		state, ret := dev.GetNvLinkState(i)
+		if ret != nvml.SUCCESS || state != nvml.FEATURE_ENABLED {
+			continue
+		}
+		version, ret := dev.GetNvLinkVersion(i)
+		if ret != nvml.SUCCESS {
+			return nvml.NVLINK_VERSION_INVALID
+		}
+		return nvml.NvlinkVersion(version)
+	}
+	return nvml.NVLINK_VERSION_INVALID
+}
+
+// getAllNvLinkRemotes returns the PCI info for all devices attached to the
+// specified device by an NVLink, and counts the NVLinks attached to NVSwitches.
+// Drivers that cannot report the remote device type are assumed to have no
+// NVSwitches.
+func getAllNvLinkRemotes(dev device.Device) (NVLinkRemotes, error) {
+	var remotes NVLinkRemotes
 	for i := 0; i < nvml.NVLINK_MAX_LINKS; i++ {
 		state, ret := dev.GetNvLinkState(i)
 		if ret == nvml.ERROR_NOT_SUPPORTED || ret == nvml.ERROR_INVALID_ARGUMENT {
 			continue
 		}
 		if ret != nvml.SUCCESS {
-			return nil, fmt.Errorf("failed to get nvlink state: %v", ret)
+			return NVLinkRemotes{}, fmt.Errorf("failed to get nvlink state: %v", ret)
 		}
 		if state != nvml.FEATURE_ENABLED {
 			continue
 		}
+		deviceType, ret := dev.GetNvLinkRemoteDeviceType(i)
+		if ret == nvml.SUCCESS && deviceType == nvml.NVLINK_DEVICE_TYPE_SWITCH {
+			remotes.NVSwitchLinks++
+			continue
+		}
 		pciInfo, ret := dev.GetNvLinkRemotePciInfo(i)
 		if ret == nvml.ERROR_NOT_SUPPORTED || ret == nvml.ERROR_INVALID_ARGUMENT {
 			continue
 		}
 		if ret != nvml.SUCCESS {
-			return nil, fmt.Errorf("failed to get remote pci info: %v", ret)
+			return NVLinkRemotes{}, fmt.Errorf("failed to get remote pci info: %v", ret)
 		}
-		pciInfos = append(pciInfos, PciInfo(pciInfo))
+		remotes.GPUs = append(remotes.GPUs, PciInfo(pciInfo))
 	}
 
-	return pciInfos, nil
+	return remotes, nil
 }
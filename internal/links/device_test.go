@@ -0,0 +1,78 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package links
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestP2PLinkTypeNames(t *testing.T) {
+	for l := P2PLinkCrossCPU; l <= EighteenNVLINKLinks; l++ {
+		parsed, err := ParseP2PLinkType(l.String())
+		require.NoError(t, err)
+		require.Equal(t, l, parsed)
+
+		parsed, err = ParseAbbreviation(l.Abbreviation())
+		require.NoError(t, err)
+		if l == P2PLinkSameBoard {
+			require.Equal(t, P2PLinkSingleSwitch, parsed)
+			continue
+		}
+		require.Equal(t, l, parsed)
+	}
+
+	_, err := ParseP2PLinkType("P2PLinkUnknown")
+	require.Error(t, err)
+	for _, abbreviation := range []string{"X", "NV0", "NV19", "NV-1", "NV02", ""} {
+		_, err := ParseAbbreviation(abbreviation)
+		require.Error(t, err, abbreviation)
+	}
+
+	parsed, err := ParseAbbreviation("SOC")
+	require.NoError(t, err)
+	require.Equal(t, P2PLinkCrossCPU, parsed)
+}
+
+func TestNVLinkCount(t *testing.T) {
+	require.Equal(t, 0, P2PLinkUnknown.NVLinkCount())
+	require.Equal(t, 0, P2PLinkSameCPU.NVLinkCount())
+	require.Equal(t, 1, SingleNVLINKLink.NVLinkCount())
+	require.Equal(t, 12, TwelveNVLINKLinks.NVLinkCount())
+	require.Equal(t, 18, EighteenNVLINKLinks.NVLinkCount())
+}
+
+func TestNVLinkRemotes(t *testing.T) {
+	peer := pciInfo("00000000:3B:00.0")
+	direct := NVLinkRemotes{GPUs: []PciInfo{peer, peer}}
+	require.Equal(t, TwoNVLINKLinks, direct.NVLinkTo(peer.BusID()))
+	require.Equal(t, P2PLinkUnknown, direct.NVLinkTo(pciInfo("00000000:86:00.0").BusID()))
+	require.Equal(t, P2PLinkUnknown, direct.NVSwitchLinkTo(NVLinkRemotes{NVSwitchLinks: 12}))
+
+	a100 := NVLinkRemotes{NVSwitchLinks: 12}
+	require.Equal(t, TwelveNVLINKLinks, a100.NVSwitchLinkTo(a100))
+	require.Equal(t, SixNVLINKLinks, a100.NVSwitchLinkTo(NVLinkRemotes{NVSwitchLinks: 6}))
+	require.Equal(t, P2PLinkUnknown, a100.NVLinkTo(peer.BusID()))
+}
+
+func pciInfo(busID string) PciInfo {
+	var info nvml.PciInfo
+	copy(info.BusId[:], busID)
+	return PciInfo(info)
+}
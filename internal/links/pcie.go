@@ -0,0 +1,264 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package links
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PCIeNodeType defines the role of a node in the PCIe hierarchy.
+type PCIeNodeType uint
+
+// The following constants define the types of nodes in the PCIe hierarchy.
+const (
+	PCIeRootComplex PCIeNodeType = iota
+	PCIeRootPort
+	PCIeSwitchUpstreamPort
+	PCIeSwitchDownstreamPort
+	PCIeEndpoint
+)
+
+// String returns the string representation of the PCIe node type.
+func (t PCIeNodeType) String() string {
+	switch t {
+	case PCIeRootComplex:
+		return "RootComplex"
+	case PCIeRootPort:
+		return "RootPort"
+	case PCIeSwitchUpstreamPort:
+		return "SwitchUpstreamPort"
+	case PCIeSwitchDownstreamPort:
+		return "SwitchDownstreamPort"
+	case PCIeEndpoint:
+		return "Endpoint"
+	default:
+		return fmt.Sprintf("UNKNOWN (%v)", uint(t))
+	}
+}
+
+// PCIeLinkStatus describes the speed and width of a PCIe link. A zero value
+// indicates that the information is not available.
+type PCIeLinkStatus struct {
+	// Speed is the transfer rate of the link in GT/s.
+	Speed float64
+	// Width is the number of lanes of the link.
+	Width int
+}
+
+// Generation returns the PCIe generation corresponding to the link speed, or
+// 0 if the speed is unknown.
+func (l PCIeLinkStatus) Generation() int {
+	switch {
+	case l.Speed >= 64:
+		return 6
+	case l.Speed >= 32:
+		return 5
+	case l.Speed >= 16:
+		return 4
+	case l.Speed >= 8:
+		return 3
+	case l.Speed >= 5:
+		return 2
+	case l.Speed >= 2.5:
+		return 1
+	}
+	return 0
+}
+
+// String returns a compact representation of the link, e.g. Gen4 x16.
+func (l PCIeLinkStatus) String() string {
+	if l.Speed == 0 && l.Width == 0 {
+		return "N/A"
+	}
+	return fmt.Sprintf("Gen%d x%d", l.Generation(), l.Width)
+}
+
+// PCIeNode is a node in the PCIe hierarchy. Root complexes are identified by
+// their sysfs name (e.g. pci0000:00), all other nodes by their PCI bus ID.
+type PCIeNode struct {
+	BusID    string
+	Type     PCIeNodeType
+	Parent   *PCIeNode
+	Children []*PCIeNode
+	// CurrentLink and MaxLink describe the link to the parent of the node.
+	CurrentLink PCIeLinkStatus
+	MaxLink     PCIeLinkStatus
+}
+
+// Downtrained returns true if the link to the parent of the node runs at a
+// lower width than it supports. The speed is not compared, since idle GPUs
+// routinely drop their links to a lower speed to save power. A current width
+// that could not be read is treated as unknown, and the link is not reported.
+func (n *PCIeNode) Downtrained() bool {
+	if n.CurrentLink.Width == 0 {
+		return false
+	}
+	return n.CurrentLink.Width < n.MaxLink.Width
+}
+
+// PathDowntrained returns true if any link between the node and its root
+// complex is downtrained.
+func (n *PCIeNode) PathDowntrained() bool {
+	for node := n; node != nil; node = node.Parent {
+		if node.Downtrained() {
+			return true
+		}
+	}
+	return false
+}
+
+// Switch returns the upstream port of the closest PCIe switch above the node,
+// or nil if the node is not behind a switch.
+func (n *PCIeNode) Switch() *PCIeNode {
+	for node := n.Parent; node != nil; node = node.Parent {
+		if node.Type == PCIeSwitchUpstreamPort {
+			return node
+		}
+	}
+	return nil
+}
+
+// RootComplex returns the root complex the node belongs to.
+func (n *PCIeNode) RootComplex() *PCIeNode {
+	node := n
+	for node.Parent != nil {
+		node = node.Parent
+	}
+	return node
+}
+
+// PCIeTree holds the part of the PCIe hierarchy that leads to a set of devices.
+type PCIeTree struct {
+	RootComplexes []*PCIeNode
+	nodes         map[string]*PCIeNode
+}
+
+// Node returns the node with the specified bus ID, or nil if it is not part
+// of the tree.
+func (t *PCIeTree) Node(busID string) *PCIeNode {
+	return t.nodes[strings.ToLower(busID)]
+}
+
+// PCIeTree builds the PCIe hierarchy leading to the devices with the specified
+// bus IDs by walking their sysfs device paths. Devices that cannot be
+// resolved in sysfs are left out of the tree.
+func (s Sysfs) PCIeTree(busIDs []string) *PCIeTree {
+	tree := &PCIeTree{
+		nodes: make(map[string]*PCIeNode),
+	}
+
+	for _, busID := range busIDs {
+		path, err := filepath.EvalSymlinks(s.pciDevicePath(busID))
+		if err != nil {
+			continue
+		}
+
+		// The resolved path is of the form:
+		//   <root>/devices/pci0000:00/0000:00:01.0/0000:01:00.0/.../<busID>
+		// The components from the root complex onwards form the path
+		// through the hierarchy.
+		components := strings.Split(filepath.ToSlash(path), "/")
+		start := -1
+		for i, c := range components {
+			if strings.HasPrefix(c, "pci") && strings.Contains(c, ":") {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			continue
+		}
+
+		var parent *PCIeNode
+		dir := filepath.FromSlash(strings.Join(components[:start], "/"))
+		for _, name := range components[start:] {
+			dir = filepath.Join(dir, name)
+			node := tree.nodes[name]
+			if node == nil {
+				node = &PCIeNode{
+					BusID:  name,
+					Parent: parent,
+				}
+				if parent == nil {
+					tree.RootComplexes = append(tree.RootComplexes, node)
+				} else {
+					parent.Children = append(parent.Children, node)
+					node.CurrentLink = readPCIeLinkStatus(dir, "current")
+					node.MaxLink = readPCIeLinkStatus(dir, "max")
+				}
+				tree.nodes[name] = node
+			}
+			parent = node
+		}
+	}
+
+	for _, root := range tree.RootComplexes {
+		classify(root, PCIeRootComplex)
+	}
+
+	return tree
+}
+
+// classify assigns node types based on the position of each node in the
+// hierarchy. Leaves are endpoints, bridges directly below a root complex are
+// root ports, and the remaining bridges alternate between switch upstream and
+// downstream ports.
+func classify(node *PCIeNode, nodeType PCIeNodeType) {
+	node.Type = nodeType
+	if len(node.Children) == 0 && nodeType != PCIeRootComplex {
+		node.Type = PCIeEndpoint
+		return
+	}
+
+	var childType PCIeNodeType
+	switch nodeType {
+	case PCIeRootComplex:
+		childType = PCIeRootPort
+	case PCIeRootPort, PCIeSwitchDownstreamPort:
+		childType = PCIeSwitchUpstreamPort
+	default:
+		childType = PCIeSwitchDownstreamPort
+	}
+	for _, child := range node.Children {
+		classify(child, childType)
+	}
+}
+
+// readPCIeLinkStatus reads the <prefix>_link_speed and <prefix>_link_width
+// attributes of a PCI device. Missing or malformed attributes are returned
+// as zero values.
+func readPCIeLinkStatus(dir string, prefix string) PCIeLinkStatus {
+	var status PCIeLinkStatus
+
+	// The link speed is reported as e.g. "16.0 GT/s PCIe" or "8 GT/s".
+	if b, err := os.ReadFile(filepath.Join(dir, prefix+"_link_speed")); err == nil {
+		fields := strings.Fields(string(b))
+		if len(fields) > 0 {
+			status.Speed, _ = strconv.ParseFloat(fields[0], 64)
+		}
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, prefix+"_link_width")); err == nil {
+		status.Width, _ = strconv.Atoi(string(bytes.TrimSpace(b)))
+	}
+
+	return status
+}
@@ -0,0 +1,86 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package links
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-gpuallocator/internal/sysfstest"
+)
+
+func pcieLink(speed string, width string) sysfstest.Attributes {
+	return sysfstest.Attributes{
+		"current_link_speed": speed + " GT/s PCIe",
+		"current_link_width": width,
+		"max_link_speed":     "16.0 GT/s PCIe",
+		"max_link_width":     "16",
+	}
+}
+
+func TestPCIeTree(t *testing.T) {
+	sysfs := sysfstest.New(t).
+		AddPCIDevice("0000:00:01.0", "", pcieLink("16.0", "16")).
+		AddPCIDevice("0000:01:00.0", "0000:00:01.0", pcieLink("16.0", "16")).
+		AddPCIDevice("0000:02:08.0", "0000:01:00.0", pcieLink("16.0", "16")).
+		AddPCIDevice("0000:03:00.0", "0000:02:08.0", pcieLink("16.0", "16")).
+		AddPCIDevice("0000:02:10.0", "0000:01:00.0", pcieLink("16.0", "16")).
+		AddPCIDevice("0000:04:00.0", "0000:02:10.0", pcieLink("8.0", "16")).
+		AddPCIDevice("0000:00:02.0", "", pcieLink("16.0", "16")).
+		AddPCIDevice("0000:05:00.0", "0000:00:02.0", pcieLink("16.0", "8")).
+		AddPCIDevice("0000:00:03.0", "", pcieLink("16.0", "16")).
+		AddPCIDevice("0000:06:00.0", "0000:00:03.0", pcieLink("Unknown", ""))
+
+	tree := Sysfs(sysfs.Root).PCIeTree([]string{"0000:03:00.0", "0000:04:00.0", "0000:05:00.0", "0000:06:00.0", "0000:ff:00.0"})
+
+	require.Len(t, tree.RootComplexes, 1)
+	root := tree.RootComplexes[0]
+	require.Equal(t, "pci0000:00", root.BusID)
+	require.Equal(t, PCIeRootComplex, root.Type)
+	require.Len(t, root.Children, 3)
+
+	gpu0 := tree.Node("0000:03:00.0")
+	gpu1 := tree.Node("0000:04:00.0")
+	gpu2 := tree.Node("0000:05:00.0")
+	require.Nil(t, tree.Node("0000:ff:00.0"))
+
+	require.Equal(t, PCIeEndpoint, gpu0.Type)
+	require.Equal(t, PCIeSwitchDownstreamPort, gpu0.Parent.Type)
+	require.Equal(t, PCIeSwitchUpstreamPort, gpu0.Parent.Parent.Type)
+	require.Equal(t, PCIeRootPort, gpu0.Parent.Parent.Parent.Type)
+	require.Equal(t, PCIeRootPort, gpu2.Parent.Type)
+
+	require.NotNil(t, gpu0.Switch())
+	require.Same(t, gpu0.Switch(), gpu1.Switch())
+	require.Nil(t, gpu2.Switch())
+	require.Same(t, root, gpu2.RootComplex())
+
+	require.False(t, gpu0.PathDowntrained())
+	// An idle GPU running its link at a lower speed is not downtrained.
+	require.False(t, gpu1.Downtrained())
+	require.True(t, gpu2.Downtrained())
+	require.True(t, gpu2.PathDowntrained())
+	require.Equal(t, "Gen3 x16", gpu1.CurrentLink.String())
+	require.Equal(t, "Gen4 x16", gpu1.MaxLink.String())
+	require.Equal(t, "Gen4 x8", gpu2.CurrentLink.String())
+
+	gpu3 := tree.Node("0000:06:00.0")
+	require.Zero(t, gpu3.CurrentLink.Width)
+	require.False(t, gpu3.Downtrained())
+	require.False(t, gpu3.PathDowntrained())
+}
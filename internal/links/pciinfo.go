@@ -17,10 +17,6 @@
 package links
 
 import (
-	"bytes"
-	"fmt"
-	"os"
-	"strconv"
 	"strings"
 
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
@@ -50,25 +46,11 @@ func (p PciInfo) BusID() string {
 // CPUAffinity returns the CPU affinity associated with a specified PCI device.
 // If NUMA information is not available, this returns nil.
 func (p PciInfo) CPUAffinity() *uint {
-	node := p.NumaNode()
-	if node < 0 {
-		return nil
-	}
-	affinity := uint(node)
-	return &affinity
+	return DefaultSysfs.CPUAffinity(p.BusID())
 }
 
 // NumaNode returns the numa node associates with a PCI device.
 // If numa is unsupported, -1 is returned.
 func (p PciInfo) NumaNode() int64 {
-	// Read the numa_node file associated with the PCI Device Info
-	b, err := os.ReadFile(fmt.Sprintf("/sys/bus/pci/devices/%s/numa_node", p.BusID()))
-	if err != nil {
-		return -1
-	}
-	node, err := strconv.ParseInt(string(bytes.TrimSpace(b)), 10, 64)
-	if err != nil {
-		return -1
-	}
-	return node
+	return DefaultSysfs.NumaNode(p.BusID())
 }
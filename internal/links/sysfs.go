@@ -0,0 +1,96 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package links
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Sysfs is the root of a sysfs mount used to query PCI device attributes.
+type Sysfs string
+
+// DefaultSysfs is the sysfs mount of the host.
+const DefaultSysfs Sysfs = "/sys"
+
+// pciDevicePath returns the path of the sysfs directory for a PCI device.
+func (s Sysfs) pciDevicePath(busID string) string {
+	return filepath.Join(string(s), "bus", "pci", "devices", strings.ToLower(busID))
+}
+
+// NumaNode returns the numa node associated with the PCI device with the
+// specified bus ID. If numa is unsupported, -1 is returned.
+func (s Sysfs) NumaNode(busID string) int64 {
+	// Read the numa_node file associated with the PCI Device
+	b, err := os.ReadFile(filepath.Join(s.pciDevicePath(busID), "numa_node"))
+	if err != nil {
+		return -1
+	}
+	node, err := strconv.ParseInt(string(bytes.TrimSpace(b)), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return node
+}
+
+// CPUAffinity returns the NUMA node of the PCI device with the specified bus
+// ID as the CPU affinity of the device. If NUMA information is not
+// available, this returns nil.
+func (s Sysfs) CPUAffinity(busID string) *uint {
+	node := s.NumaNode(busID)
+	if node < 0 {
+		return nil
+	}
+	affinity := uint(node)
+	return &affinity
+}
+
+// RDMADevices returns the PCI bus IDs of the RDMA devices registered under
+// class/infiniband, keyed by device name (e.g. mlx5_0).
+func (s Sysfs) RDMADevices() (map[string]string, error) {
+	entries, err := os.ReadDir(filepath.Join(string(s), "class", "infiniband"))
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make(map[string]string)
+	for _, entry := range entries {
+		target, err := filepath.EvalSymlinks(filepath.Join(string(s), "class", "infiniband", entry.Name(), "device"))
+		if err != nil {
+			continue
+		}
+		devices[entry.Name()] = filepath.Base(target)
+	}
+	return devices, nil
+}
+
+// LocalCPUList returns the contents of the local_cpulist file of the PCI
+// device with the specified bus ID. This lists the CPUs that are local to
+// the device in the Linux list format (e.g. 0-15,32-47).
+func (s Sysfs) LocalCPUList(busID string) (string, error) {
+	b, err := os.ReadFile(filepath.Join(s.pciDevicePath(busID), "local_cpulist"))
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(b)), nil
+}
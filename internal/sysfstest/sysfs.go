@@ -0,0 +1,128 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package sysfstest builds fake sysfs trees for use in tests.
+package sysfstest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Tree is a fake sysfs tree rooted in a temporary directory.
+//
+// PCI devices are laid out as in a real sysfs mount: each device is a
+// directory nested below its parent bridge under devices/pci<domain>:<bus>,
+// and bus/pci/devices/<busID> is a symlink to that directory.
+type Tree struct {
+	t       testing.TB
+	Root    string
+	devices map[string]string
+}
+
+// Attributes holds the attribute files of a fake sysfs device, keyed by file name.
+type Attributes map[string]string
+
+// New creates an empty fake sysfs tree that is removed at the end of the test.
+func New(t testing.TB) *Tree {
+	return &Tree{
+		t:       t,
+		Root:    t.TempDir(),
+		devices: make(map[string]string),
+	}
+}
+
+// AddPCIDevice adds a PCI device with the specified bus ID (e.g.
+// 0000:3b:00.0) and attributes (e.g. numa_node, local_cpulist). The device is
+// placed below the device with bus ID 'parent', which must already have been
+// added. If 'parent' is empty the device is attached directly to the root
+// complex for its domain and bus.
+func (tree *Tree) AddPCIDevice(busID string, parent string, attrs Attributes) *Tree {
+	tree.t.Helper()
+
+	busID = strings.ToLower(busID)
+	var dir string
+	if parent == "" {
+		dir = filepath.Join(tree.Root, "devices", "pci"+rootBus(busID), busID)
+	} else {
+		parentDir, ok := tree.devices[strings.ToLower(parent)]
+		if !ok {
+			tree.t.Fatalf("parent device %v of %v does not exist", parent, busID)
+		}
+		dir = filepath.Join(parentDir, busID)
+	}
+
+	tree.mkdir(dir)
+	tree.writeAttributes(dir, attrs)
+	tree.devices[busID] = dir
+
+	link := filepath.Join(tree.Root, "bus", "pci", "devices", busID)
+	tree.mkdir(filepath.Dir(link))
+	tree.symlink(dir, link)
+
+	return tree
+}
+
+// AddRDMADevice registers an RDMA device (e.g. mlx5_0) under class/infiniband
+// that is backed by the PCI device with the specified bus ID.
+func (tree *Tree) AddRDMADevice(name string, busID string) *Tree {
+	tree.t.Helper()
+
+	dir, ok := tree.devices[strings.ToLower(busID)]
+	if !ok {
+		tree.t.Fatalf("PCI device %v of %v does not exist", busID, name)
+	}
+
+	class := filepath.Join(tree.Root, "class", "infiniband", name)
+	tree.mkdir(class)
+	tree.symlink(dir, filepath.Join(class, "device"))
+
+	return tree
+}
+
+// rootBus returns the <domain>:<bus> part of a PCI bus ID.
+func rootBus(busID string) string {
+	parts := strings.SplitN(busID, ":", 3)
+	if len(parts) < 2 {
+		return busID
+	}
+	return parts[0] + ":" + parts[1]
+}
+
+func (tree *Tree) writeAttributes(dir string, attrs Attributes) {
+	tree.t.Helper()
+	for name, value := range attrs {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(value+"\n"), 0600); err != nil {
+			tree.t.Fatalf("failed to write attribute %v: %v", name, err)
+		}
+	}
+}
+
+func (tree *Tree) mkdir(dir string) {
+	tree.t.Helper()
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		tree.t.Fatalf("failed to create directory %v: %v", dir, err)
+	}
+}
+
+func (tree *Tree) symlink(target string, link string) {
+	tree.t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		tree.t.Fatalf("failed to create symlink %v: %v", link, err)
+	}
+}